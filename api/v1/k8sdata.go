@@ -1,92 +1,583 @@
 package kollect
 
+// NodeInfo describes a node. PodCount is null when the node's pods could not
+// be counted.
 type NodeInfo struct {
-	Name    string
-	Roles   string
-	Age     string
-	Version string
-	OSImage string
+	Name                    string          `json:"name"`
+	CreationTimestamp       string          `json:"creationTimestamp"`
+	Roles                   []string        `json:"roles,omitempty"`
+	Version                 string          `json:"version"`
+	OSImage                 string          `json:"osImage"`
+	KernelVersion           string          `json:"kernelVersion"`
+	ContainerRuntimeVersion string          `json:"containerRuntimeVersion"`
+	OperatingSystem         string          `json:"operatingSystem"`
+	Architecture            string          `json:"architecture"`
+	InstanceType            string          `json:"instanceType"`
+	Region                  string          `json:"region"`
+	Zone                    string          `json:"zone"`
+	Capacity                NodeResources   `json:"capacity"`
+	Allocatable             NodeResources   `json:"allocatable"`
+	PodCount                *int            `json:"podCount"`
+	MaxPods                 int64           `json:"maxPods"`
+	Unschedulable           bool            `json:"unschedulable"`
+	Ready                   bool            `json:"ready"`
+	Taints                  []NodeTaint     `json:"taints,omitempty"`
+	Conditions              []NodeCondition `json:"conditions,omitempty"`
+}
+
+// NodeResources holds resource quantities in Kubernetes notation, e.g.
+// "3920m" CPU or "15Gi" memory.
+type NodeResources struct {
+	CPU              string `json:"cpu"`
+	Memory           string `json:"memory"`
+	EphemeralStorage string `json:"ephemeralStorage"`
+	Pods             string `json:"pods"`
+}
+
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
+}
+
+type NodeCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// ResourceSummary totals node resources across the cluster. Pressure counts
+// nodes reporting each pressure condition, e.g. "MemoryPressure". PodCount is
+// null when any node's pod count is unknown.
+type ResourceSummary struct {
+	Nodes         int            `json:"nodes"`
+	ReadyNodes    int            `json:"readyNodes"`
+	Capacity      NodeResources  `json:"capacity"`
+	Allocatable   NodeResources  `json:"allocatable"`
+	PodCount      *int           `json:"podCount"`
+	MaxPods       int64          `json:"maxPods"`
+	Architectures map[string]int `json:"architectures,omitempty"`
+	Zones         map[string]int `json:"zones,omitempty"`
+	Pressure      map[string]int `json:"pressure,omitempty"`
 }
 
 type PodsInfo struct {
-	Name      string
-	Namespace string
-	Status    string
+	Name                   string          `json:"name"`
+	Namespace              string          `json:"namespace"`
+	CreationTimestamp      string          `json:"creationTimestamp"`
+	Status                 string          `json:"status"`
+	NodeName               string          `json:"nodeName"`
+	QOSClass               string          `json:"qosClass"`
+	OwnerKind              string          `json:"ownerKind"`
+	OwnerName              string          `json:"ownerName"`
+	Containers             []ContainerInfo `json:"containers,omitempty"`
+	Volumes                []PodVolume     `json:"volumes,omitempty"`
+	PersistentVolumeClaims []string        `json:"persistentVolumeClaims,omitempty"`
 }
 
+// ContainerInfo describes one container of a pod, including init
+// containers. ImageDigest is the digest the kubelet resolved Image to.
+type ContainerInfo struct {
+	Name                  string             `json:"name"`
+	Init                  bool               `json:"init"`
+	Image                 string             `json:"image"`
+	ImageDigest           string             `json:"imageDigest"`
+	Requests              ContainerResources `json:"requests"`
+	Limits                ContainerResources `json:"limits"`
+	Ready                 bool               `json:"ready"`
+	State                 string             `json:"state"`
+	RestartCount          int32              `json:"restartCount"`
+	LastTerminationReason string             `json:"lastTerminationReason"`
+}
+
+type ContainerResources struct {
+	CPU              string `json:"cpu"`
+	Memory           string `json:"memory"`
+	EphemeralStorage string `json:"ephemeralStorage"`
+}
+
+// PodVolume is a volume a pod declares. Depending on Type, Source is the
+// claim name, host path, emptyDir medium, ConfigMap or Secret name, or CSI
+// driver.
+type PodVolume struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+// Volume types used in PodVolume.
+const (
+	VolumePersistentVolumeClaim = "PersistentVolumeClaim"
+	VolumeHostPath              = "HostPath"
+	VolumeEmptyDir              = "EmptyDir"
+	VolumeConfigMap             = "ConfigMap"
+	VolumeSecret                = "Secret"
+	VolumeProjected             = "Projected"
+	VolumeDownwardAPI           = "DownwardAPI"
+	VolumeCSI                   = "CSI"
+	VolumeEphemeral             = "Ephemeral"
+	VolumeOther                 = "Other"
+)
+
 type DeploymentInfo struct {
-	Name       string
-	Namespace  string
-	Containers []string
-	Images     []string
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+	Replicas          int32    `json:"replicas"`
+	ReadyReplicas     int32    `json:"readyReplicas"`
+	Containers        []string `json:"containers,omitempty"`
+	Images            []string `json:"images,omitempty"`
 }
 
 type StatefulSetInfo struct {
-	Name          string
-	Namespace     string
-	ReadyReplicas int32
-	Image         string
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Replicas          int32  `json:"replicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	Image             string `json:"image"`
+}
+
+type DaemonSetInfo struct {
+	Name                   string   `json:"name"`
+	Namespace              string   `json:"namespace"`
+	CreationTimestamp      string   `json:"creationTimestamp"`
+	DesiredNumberScheduled int32    `json:"desiredNumberScheduled"`
+	NumberReady            int32    `json:"numberReady"`
+	Images                 []string `json:"images,omitempty"`
+}
+
+type ReplicaSetInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Replicas          int32  `json:"replicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	OwnerKind         string `json:"ownerKind"`
+	OwnerName         string `json:"ownerName"`
+}
+
+type JobInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Completions       int32  `json:"completions"`
+	Succeeded         int32  `json:"succeeded"`
+	Failed            int32  `json:"failed"`
+	Active            int32  `json:"active"`
+	OwnerKind         string `json:"ownerKind"`
+	OwnerName         string `json:"ownerName"`
+}
+
+type CronJobInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Schedule          string `json:"schedule"`
+	Suspend           bool   `json:"suspend"`
+	LastScheduleTime  string `json:"lastScheduleTime"`
+}
+
+type IngressInfo struct {
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+	IngressClass      string   `json:"ingressClass"`
+	Hosts             []string `json:"hosts,omitempty"`
+	TLS               bool     `json:"tls"`
+}
+
+// ConfigMapInfo records a ConfigMap's name and how many keys it holds; the
+// values themselves are never collected.
+type ConfigMapInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Keys              int    `json:"keys"`
+}
+
+// SecretInfo records a Secret's name, type and how many keys it holds; the
+// values themselves are never collected.
+type SecretInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Type              string `json:"type"`
+	Keys              int    `json:"keys"`
+}
+
+type HorizontalPodAutoscalerInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	TargetKind        string `json:"targetKind"`
+	TargetName        string `json:"targetName"`
+	MinReplicas       int32  `json:"minReplicas"`
+	MaxReplicas       int32  `json:"maxReplicas"`
+	CurrentReplicas   int32  `json:"currentReplicas"`
+}
+
+type PodDisruptionBudgetInfo struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	CreationTimestamp  string `json:"creationTimestamp"`
+	MinAvailable       string `json:"minAvailable"`
+	MaxUnavailable     string `json:"maxUnavailable"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+}
+
+type CustomResourceDefinitionInfo struct {
+	Name              string   `json:"name"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+	Group             string   `json:"group"`
+	Kind              string   `json:"kind"`
+	Scope             string   `json:"scope"`
+	Versions          []string `json:"versions,omitempty"`
+}
+
+// CustomResourceInstance is one object of a custom resource. Status is
+// taken from status.phase, status.state or the Ready condition, whichever
+// the resource reports.
+type CustomResourceInstance struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Status            string `json:"status"`
+}
+
+// CustomResourceInfo lists the instances of one custom resource from an
+// allow-listed API group.
+type CustomResourceInfo struct {
+	Group     string                   `json:"group"`
+	Version   string                   `json:"version"`
+	Kind      string                   `json:"kind"`
+	Resource  string                   `json:"resource"`
+	Count     int                      `json:"count"`
+	Instances []CustomResourceInstance `json:"instances,omitempty"`
 }
 
 type ServiceInfo struct {
-	Name      string
-	Namespace string
-	Type      string
-	ClusterIP string
-	Ports     string
+	Name              string        `json:"name"`
+	Namespace         string        `json:"namespace"`
+	CreationTimestamp string        `json:"creationTimestamp"`
+	Type              string        `json:"type"`
+	ClusterIP         string        `json:"clusterIP"`
+	Ports             []ServicePort `json:"ports,omitempty"`
+}
+
+// ServicePort is one port a Service exposes. TargetPort is a number or a
+// named container port; NodePort is zero unless allocated.
+type ServicePort struct {
+	Name       string `json:"name"`
+	Protocol   string `json:"protocol"`
+	Port       int32  `json:"port"`
+	TargetPort string `json:"targetPort"`
+	NodePort   int32  `json:"nodePort"`
 }
 
 type PersistentVolumeInfo struct {
-	Name            string
-	Capacity        string
-	AccessModes     string
-	Status          string
-	AssociatedClaim string
-	StorageClass    string
-	VolumeMode      string
+	Name              string   `json:"name"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+	Capacity          string   `json:"capacity"`
+	AccessModes       []string `json:"accessModes,omitempty"`
+	Status            string   `json:"status"`
+	AssociatedClaim   string   `json:"associatedClaim"`
+	ClaimNamespace    string   `json:"claimNamespace"`
+	StorageClass      string   `json:"storageClass"`
+	VolumeMode        string   `json:"volumeMode"`
+	Driver            string   `json:"driver"`
 }
 
 type PersistentVolumeClaimInfo struct {
-	Name         string
-	Namespace    string
-	Status       string
-	Volume       string
-	Capacity     string
-	AccessMode   string
-	StorageClass string
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+	Status            string   `json:"status"`
+	Volume            string   `json:"volume"`
+	Capacity          string   `json:"capacity"`
+	AccessModes       []string `json:"accessModes,omitempty"`
+	StorageClass      string   `json:"storageClass"`
 }
 
 type StorageClassInfo struct {
-	Name            string
-	Provisioner     string
-	VolumeExpansion string
+	Name              string `json:"name"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Provisioner       string `json:"provisioner"`
+	VolumeExpansion   bool   `json:"volumeExpansion"`
+	IsDefault         bool   `json:"isDefault"`
 }
 
 type VolumeSnapshotClassInfo struct {
-	Name   string
-	Driver string
+	Name              string `json:"name"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Driver            string `json:"driver"`
+	IsDefault         bool   `json:"isDefault"`
 }
 
 type VolumeSnapshotInfo struct {
-	Name              string
-	Namespace         string
-	Volume            string
-	CreationTimestamp string
-	RestoreSize       string
-	Status            bool
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	Volume            string `json:"volume"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	RestoreSize       string `json:"restoreSize"`
+	Status            bool   `json:"status"`
+}
+
+// VeleroInfo holds the Velero objects found in the cluster. It is nil when
+// Velero is not installed.
+type VeleroInfo struct {
+	Backups                 []VeleroBackupInfo                 `json:"backups,omitempty"`
+	Schedules               []VeleroScheduleInfo               `json:"schedules,omitempty"`
+	BackupStorageLocations  []VeleroBackupStorageLocationInfo  `json:"backupStorageLocations,omitempty"`
+	VolumeSnapshotLocations []VeleroVolumeSnapshotLocationInfo `json:"volumeSnapshotLocations,omitempty"`
+}
+
+// VeleroBackupInfo is a velero.io Backup. IncludedNamespaces and
+// ExcludedNamespaces hold namespace patterns; "*" selects every namespace.
+type VeleroBackupInfo struct {
+	Name                string   `json:"name"`
+	Namespace           string   `json:"namespace"`
+	CreationTimestamp   string   `json:"creationTimestamp"`
+	Schedule            string   `json:"schedule"`
+	Phase               string   `json:"phase"`
+	IncludedNamespaces  []string `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces  []string `json:"excludedNamespaces,omitempty"`
+	StorageLocation     string   `json:"storageLocation"`
+	TTL                 string   `json:"ttl"`
+	StartTimestamp      string   `json:"startTimestamp"`
+	CompletionTimestamp string   `json:"completionTimestamp"`
+	Expiration          string   `json:"expiration"`
+}
+
+type VeleroScheduleInfo struct {
+	Name               string   `json:"name"`
+	Namespace          string   `json:"namespace"`
+	CreationTimestamp  string   `json:"creationTimestamp"`
+	Schedule           string   `json:"schedule"`
+	Paused             bool     `json:"paused"`
+	Phase              string   `json:"phase"`
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+	StorageLocation    string   `json:"storageLocation"`
+	TTL                string   `json:"ttl"`
+	LastBackup         string   `json:"lastBackup"`
+}
+
+type VeleroBackupStorageLocationInfo struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	CreationTimestamp  string `json:"creationTimestamp"`
+	Provider           string `json:"provider"`
+	Bucket             string `json:"bucket"`
+	Prefix             string `json:"prefix"`
+	Default            bool   `json:"default"`
+	Phase              string `json:"phase"`
+	LastValidationTime string `json:"lastValidationTime"`
+}
+
+type VeleroVolumeSnapshotLocationInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Provider          string `json:"provider"`
+}
+
+// KastenInfo holds the Kasten K10 objects found in the cluster. It is nil
+// when K10 is not installed.
+type KastenInfo struct {
+	Policies      []KastenPolicyInfo       `json:"policies,omitempty"`
+	RestorePoints []KastenRestorePointInfo `json:"restorePoints,omitempty"`
+	Profiles      []KastenProfileInfo      `json:"profiles,omitempty"`
+}
+
+// KastenPolicyInfo is a K10 Policy. IncludedNamespaces uses the same
+// convention as VeleroBackupInfo; it is left empty when the policy selects
+// applications by labels that cannot be resolved to namespaces.
+type KastenPolicyInfo struct {
+	Name               string           `json:"name"`
+	Namespace          string           `json:"namespace"`
+	CreationTimestamp  string           `json:"creationTimestamp"`
+	Frequency          string           `json:"frequency"`
+	Paused             bool             `json:"paused"`
+	Actions            []string         `json:"actions,omitempty"`
+	IncludedNamespaces []string         `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string         `json:"excludedNamespaces,omitempty"`
+	Retention          map[string]int64 `json:"retention,omitempty"`
+	Validation         string           `json:"validation"`
+}
+
+type KastenRestorePointInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	Policy            string `json:"policy"`
+	CreationTimestamp string `json:"creationTimestamp"`
+}
+
+type KastenProfileInfo struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	Type              string `json:"type"`
+	LocationType      string `json:"locationType"`
+	ObjectStoreType   string `json:"objectStoreType"`
+	Bucket            string `json:"bucket"`
+	Region            string `json:"region"`
+	Validation        string `json:"validation"`
+}
+
+// ProtectionSchedule is a Velero Schedule or Kasten Policy that backs up a
+// namespace.
+type ProtectionSchedule struct {
+	Tool      string `json:"tool"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Schedule  string `json:"schedule"`
+	Retention string `json:"retention"`
+	Paused    bool   `json:"paused"`
+}
+
+// NamespaceProtection summarises how a namespace is backed up. Protected is
+// set when at least one unpaused schedule covers the namespace.
+type NamespaceProtection struct {
+	Namespace            string               `json:"namespace"`
+	Protected            bool                 `json:"protected"`
+	LastSuccessfulBackup string               `json:"lastSuccessfulBackup"`
+	LastBackupTool       string               `json:"lastBackupTool"`
+	LastBackupName       string               `json:"lastBackupName"`
+	Schedules            []ProtectionSchedule `json:"schedules,omitempty"`
+}
+
+// Backup tools reported in NamespaceProtection.
+const (
+	ToolVelero = "velero"
+	ToolKasten = "kasten"
+)
+
+// CollectionError records a resource that could not be listed. Forbidden is
+// set when the API server rejected the request for lack of RBAC rights.
+type CollectionError struct {
+	Resource  string `json:"resource"`
+	Reason    string `json:"reason"`
+	Forbidden bool   `json:"forbidden"`
+}
+
+// ObjectRef identifies a collected object. Namespace is empty for
+// cluster-scoped kinds.
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Edge is a directed relationship between two objects, e.g. a Pod that
+// mounts a PersistentVolumeClaim.
+type Edge struct {
+	From     ObjectRef `json:"from"`
+	To       ObjectRef `json:"to"`
+	Relation string    `json:"relation"`
+}
+
+// Relations used in Edge.
+const (
+	RelationOwns          = "owns"
+	RelationMounts        = "mounts"
+	RelationBoundTo       = "boundTo"
+	RelationUsesClass     = "usesClass"
+	RelationProvisionedBy = "provisionedBy"
+	RelationSnapshottedBy = "snapshottedBy"
+)
+
+// FootprintVolume is one claim used by an application, followed down to the
+// driver that provisions it and the snapshot classes that can snapshot it.
+type FootprintVolume struct {
+	Claim           string   `json:"claim"`
+	Volume          string   `json:"volume"`
+	Capacity        string   `json:"capacity"`
+	StorageClass    string   `json:"storageClass"`
+	Driver          string   `json:"driver"`
+	SnapshotClasses []string `json:"snapshotClasses,omitempty"`
+}
+
+// StorageFootprint lists the persistent storage used by one application,
+// identified by its top-level workload (or the Pod itself if unowned).
+type StorageFootprint struct {
+	Workload ObjectRef         `json:"workload"`
+	Volumes  []FootprintVolume `json:"volumes,omitempty"`
+}
+
+// Severities used in ReadinessFinding.
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// ReadinessFinding is one backup-readiness problem found in the cluster.
+type ReadinessFinding struct {
+	Check    string    `json:"check"`
+	Severity string    `json:"severity"`
+	Object   ObjectRef `json:"object"`
+	Message  string    `json:"message"`
+}
+
+// ReadinessReport scores how ready the cluster's storage is to be backed up
+// with CSI snapshots. Score runs from 0 to 100, and is null when the claims
+// could not be assessed.
+type ReadinessReport struct {
+	Score          *int               `json:"score"`
+	ClaimsAssessed int                `json:"claimsAssessed"`
+	ClaimsReady    int                `json:"claimsReady"`
+	SnapshotMaxAge string             `json:"snapshotMaxAge"`
+	Findings       []ReadinessFinding `json:"findings,omitempty"`
+}
+
+// ClusterInfo identifies the cluster a K8sData was collected from.
+type ClusterInfo struct {
+	Context string `json:"context"`
+	Server  string `json:"server"`
+	Version string `json:"version"`
 }
 
 type K8sData struct {
-	Nodes                  []NodeInfo
-	Namespaces             []string
-	Pods                   []PodsInfo
-	Deployments            []DeploymentInfo
-	StatefulSets           []StatefulSetInfo
-	Services               []ServiceInfo
-	PersistentVolumes      []PersistentVolumeInfo
-	PersistentVolumeClaims []PersistentVolumeClaimInfo
-	StorageClasses         []StorageClassInfo
-	VolumeSnapshotClasses  []VolumeSnapshotClassInfo
-	VolumeSnapshots        []VolumeSnapshotInfo
+	Cluster                   ClusterInfo                    `json:"cluster"`
+	Nodes                     []NodeInfo                     `json:"nodes,omitempty"`
+	ResourceSummary           *ResourceSummary               `json:"resourceSummary,omitempty"`
+	Namespaces                []string                       `json:"namespaces,omitempty"`
+	Pods                      []PodsInfo                     `json:"pods,omitempty"`
+	Deployments               []DeploymentInfo               `json:"deployments,omitempty"`
+	StatefulSets              []StatefulSetInfo              `json:"statefulSets,omitempty"`
+	DaemonSets                []DaemonSetInfo                `json:"daemonSets,omitempty"`
+	ReplicaSets               []ReplicaSetInfo               `json:"replicaSets,omitempty"`
+	Jobs                      []JobInfo                      `json:"jobs,omitempty"`
+	CronJobs                  []CronJobInfo                  `json:"cronJobs,omitempty"`
+	Services                  []ServiceInfo                  `json:"services,omitempty"`
+	Ingresses                 []IngressInfo                  `json:"ingresses,omitempty"`
+	ConfigMaps                []ConfigMapInfo                `json:"configMaps,omitempty"`
+	Secrets                   []SecretInfo                   `json:"secrets,omitempty"`
+	HorizontalPodAutoscalers  []HorizontalPodAutoscalerInfo  `json:"horizontalPodAutoscalers,omitempty"`
+	PodDisruptionBudgets      []PodDisruptionBudgetInfo      `json:"podDisruptionBudgets,omitempty"`
+	CustomResourceDefinitions []CustomResourceDefinitionInfo `json:"customResourceDefinitions,omitempty"`
+	CustomResources           []CustomResourceInfo           `json:"customResources,omitempty"`
+	PersistentVolumes         []PersistentVolumeInfo         `json:"persistentVolumes,omitempty"`
+	PersistentVolumeClaims    []PersistentVolumeClaimInfo    `json:"persistentVolumeClaims,omitempty"`
+	StorageClasses            []StorageClassInfo             `json:"storageClasses,omitempty"`
+	VolumeSnapshotClasses     []VolumeSnapshotClassInfo      `json:"volumeSnapshotClasses,omitempty"`
+	VolumeSnapshots           []VolumeSnapshotInfo           `json:"volumeSnapshots,omitempty"`
+	Velero                    *VeleroInfo                    `json:"velero,omitempty"`
+	Kasten                    *KastenInfo                    `json:"kasten,omitempty"`
+	NamespaceProtection       []NamespaceProtection          `json:"namespaceProtection,omitempty"`
+	Edges                     []Edge                         `json:"edges,omitempty"`
+	StorageFootprints         []StorageFootprint             `json:"storageFootprints,omitempty"`
+	Readiness                 *ReadinessReport               `json:"readiness,omitempty"`
+	Errors                    []CollectionError              `json:"errors,omitempty"`
 	// Add other fields as needed
 }
+
+// MultiClusterData holds one K8sData per kubeconfig context, keyed by
+// context name.
+type MultiClusterData struct {
+	Clusters map[string]K8sData `json:"clusters"`
+}
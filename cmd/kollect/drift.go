@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/history"
+)
+
+// errDriftExceeded is returned by runDrift when more drift is found than
+// the allow-list and --max-drift accept. kollect exits with status 2.
+var errDriftExceeded = errors.New("drift exceeds the allow-list")
+
+// runDrift implements "kollect drift --baseline <file>": it collects live
+// data and compares the tracked fields against the baseline.
+func runDrift(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("drift", flag.ExitOnError)
+	collectOpts := addCollectFlags(fs)
+	baseline := fs.String("baseline", "", "Baseline file written with --output, or a run in the snapshot store")
+	storeDir := fs.String("store", os.Getenv(storeEnv), "Snapshot store directory the live run is saved to and baselines are read from")
+	var allow stringList
+	fs.Var(&allow, "allow", "Accepted drift as platform:type:key:field, * matches anything (repeatable)")
+	allowFile := fs.String("allow-file", "", "File of accepted drift patterns, one per line")
+	var fields stringList
+	fs.Var(&fields, "fields", "Tracked fields of a resource type as platform/type:field,field, replacing the defaults for that type; no fields stops tracking it (repeatable)")
+	maxDrift := fs.Int("max-drift", 0, "Number of drifted fields outside the allow-list tolerated before failing")
+	asJSON := fs.Bool("json", false, "Print the drift report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: kollect drift --baseline <file> [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *baseline == "" {
+		fs.Usage()
+		return fmt.Errorf("drift needs a --baseline")
+	}
+
+	patterns := []string(allow)
+	if *allowFile != "" {
+		b, err := os.ReadFile(*allowFile)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, strings.Split(string(b), "\n")...)
+	}
+	allowList, err := history.ParseAllowList(patterns)
+	if err != nil {
+		return err
+	}
+	rules, err := history.ParseDriftRules(history.DefaultDriftRules, fields)
+	if err != nil {
+		return err
+	}
+
+	base, err := loadRun(*storeDir, *baseline)
+	if err != nil {
+		return err
+	}
+	cfg, names, err := collectOpts.config()
+	if err != nil {
+		return err
+	}
+	doc, err := collectData(context.Background(), names, cfg)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if source := doc.Sources[name]; source.Error != "" {
+			return fmt.Errorf("could not collect %s: %s", name, source.Error)
+		}
+	}
+	if *storeDir != "" {
+		if err := saveRun(*storeDir, doc); err != nil {
+			log.Printf("Warning: Error saving run to snapshot store: %v", err)
+		}
+	}
+
+	drifts, err := history.DetectDrift(base, doc, rules)
+	if err != nil {
+		return err
+	}
+	unexpected := 0
+	for i := range drifts {
+		drifts[i].Allowed = allowList.Allows(drifts[i])
+		if !drifts[i].Allowed {
+			unexpected++
+		}
+	}
+	if *asJSON {
+		if drifts == nil {
+			drifts = []history.Drift{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(drifts); err != nil {
+			return err
+		}
+	} else {
+		history.WriteDrift(w, drifts)
+	}
+	if unexpected > *maxDrift {
+		return fmt.Errorf("%w: %d drifted fields, %d tolerated", errDriftExceeded, unexpected, *maxDrift)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/export"
+)
+
+// Output formats accepted by --format and /api/export.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+)
+
+func validFormat(format string) bool {
+	return format == formatJSON || format == formatCSV || format == formatXLSX
+}
+
+// exportFile writes data to name as CSV files in the directory name, or as
+// an XLSX workbook.
+func exportFile(data interface{}, name, format string) error {
+	tables, err := tables(data)
+	if err != nil {
+		return err
+	}
+	if format == formatCSV {
+		return export.WriteCSVDir(name, tables)
+	}
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteXLSX(file, tables); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// serveExport implements /api/export?format=json|csv|xlsx. CSV files are
+// returned together in a zip archive.
+func serveExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = formatJSON
+	}
+	if !validFormat(format) {
+		http.Error(w, fmt.Sprintf("Unsupported format %q, expected json, csv or xlsx", format), http.StatusBadRequest)
+		return
+	}
+	dataMutex.Lock()
+	current := data
+	dataMutex.Unlock()
+
+	if format == formatJSON {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="kollect.json"`)
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(current); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	tables, err := tables(current)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if format == formatCSV {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="kollect-csv.zip"`)
+		err = export.WriteCSVZip(w, tables)
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", `attachment; filename="kollect.xlsx"`)
+		err = export.WriteXLSX(w, tables)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// tables flattens data, which is a collector.Document unless it was
+// imported through /api/import.
+func tables(data interface{}) ([]export.Table, error) {
+	doc, ok := data.(collector.Document)
+	if !ok {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, err
+		}
+	}
+	return export.Tables(doc)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/history"
+)
+
+// storeEnv names the environment variable holding the default snapshot
+// store directory.
+const storeEnv = "KOLLECT_STORE"
+
+// runDiff implements "kollect diff <run-a> <run-b>". Each run is a run ID,
+// a unique prefix of one, "latest", "latest~N" or the path of a saved JSON
+// file.
+func runDiff(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	storeDir := fs.String("store", os.Getenv(storeEnv), "Snapshot store directory")
+	asJSON := fs.Bool("json", false, "Print the diff as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: kollect diff [flags] <run-a> <run-b>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("diff needs two runs")
+	}
+	from, err := loadRun(*storeDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	to, err := loadRun(*storeDir, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	diff, err := history.Compare(from, to)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+	diff.WriteText(w)
+	return nil
+}
+
+// runRuns implements "kollect runs", listing the runs in the store.
+func runRuns(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("runs", flag.ExitOnError)
+	storeDir := fs.String("store", os.Getenv(storeEnv), "Snapshot store directory")
+	fs.Parse(args)
+	store, err := history.Open(*storeDir)
+	if err != nil {
+		return err
+	}
+	runs, err := store.List()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCOLLECTED\tVERSION\tINVENTORIES\tERRORS")
+	for _, run := range runs {
+		var failed []string
+		for name, source := range run.Sources {
+			if source.Error != "" {
+				failed = append(failed, name)
+			}
+		}
+		sort.Strings(failed)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", run.ID, run.CollectedAt.Format(time.RFC3339), run.ToolVersion,
+			strings.Join(run.Inventories, ","), strings.Join(failed, ","))
+	}
+	return tw.Flush()
+}
+
+// loadRun reads ref from the store, or from a file if ref names one.
+func loadRun(storeDir, ref string) (collector.Document, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return history.ReadFile(ref)
+	}
+	if storeDir == "" {
+		return collector.Document{}, fmt.Errorf("%s is not a file and no snapshot store is set (use --store or %s)", ref, storeEnv)
+	}
+	store, err := history.Open(storeDir)
+	if err != nil {
+		return collector.Document{}, err
+	}
+	id, err := store.Resolve(ref)
+	if err != nil {
+		return collector.Document{}, err
+	}
+	return store.Load(id)
+}
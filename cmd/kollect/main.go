@@ -3,39 +3,99 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	k8sdata "github.com/michaelcade/kollect/api/v1"
 	"github.com/michaelcade/kollect/pkg/aws"
 	"github.com/michaelcade/kollect/pkg/azure"
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/export"
+	"github.com/michaelcade/kollect/pkg/history"
 	"github.com/michaelcade/kollect/pkg/kollect"
-	"github.com/michaelcade/kollect/pkg/veeam"
+	"github.com/michaelcade/kollect/pkg/metrics"
+	_ "github.com/michaelcade/kollect/pkg/veeam"
 )
 
+//go:generate sh -c "go run . schema > ../../api/v1/kollect.schema.json"
+
 var (
 	dataMutex sync.Mutex
 	data      interface{}
+	// dataCounts are the /metrics resource counts of data.
+	dataCounts metrics.Counts
 )
 
+// setData replaces the served data and counts its resources for /metrics,
+// so that scrapes only print the counts.
+func setData(v interface{}) {
+	var counts metrics.Counts
+	if doc, ok := v.(collector.Document); ok {
+		var err error
+		if counts, err = metrics.Count(doc); err != nil {
+			log.Printf("Warning: could not count resources for metrics: %v", err)
+		}
+	}
+	dataMutex.Lock()
+	data = v
+	dataCounts = counts
+	dataMutex.Unlock()
+}
+
+// subcommands run instead of a collection when named as the first argument.
+var subcommands = map[string]func(args []string, w io.Writer) error{
+	"schema": func(args []string, w io.Writer) error { return writeSchema(w) },
+	"diff":   runDiff,
+	"runs":   runRuns,
+	"drift":  runDrift,
+	"serve":  runServe,
+}
+
 func main() {
-	storageOnly := flag.Bool("storage", false, "Collect only storage-related objects (Kubernetes Only)")
-	kubeconfig := flag.String("kubeconfig", filepath.Join(os.Getenv("HOME"), ".kube", "config"), "Path to the kubeconfig file")
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:], os.Stdout); err != nil {
+				if errors.Is(err, errDriftExceeded) {
+					log.Print(err)
+					os.Exit(2)
+				}
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	collectOpts := addCollectFlags(flag.CommandLine)
 	browser := flag.Bool("browser", false, "Open the web interface in a browser")
 	output := flag.String("output", "", "Output file to save the collected data")
-	inventoryType := flag.String("inventory", "kubernetes", "Type of inventory to collect (kubernetes/aws/azure/veeam)")
-	baseURL := flag.String("veeam-url", "", "Veeam server URL")
-	username := flag.String("veeam-username", "", "Veeam username")
-	password := flag.String("veeam-password", "", "Veeam password")
+	format := flag.String("format", formatJSON, "Format of the --output file: json, csv (a directory with one file per resource type) or xlsx")
+	storeDir := flag.String("store", os.Getenv(storeEnv), "Snapshot store directory every run is saved to (default $"+storeEnv+")")
+	readiness := flag.Bool("readiness", false, "Print the Kubernetes backup-readiness report as a table instead of JSON")
+	table := flag.Bool("table", false, "Print the collected resources as one table per type, with ages, instead of JSON")
 	help := flag.Bool("help", false, "Show help message")
 	flag.Parse()
 	if *help {
 		fmt.Println("Usage: kollect [flags]")
+		fmt.Println("       kollect schema    Print the JSON Schema of the output")
+		fmt.Println("       kollect runs      List the runs in the snapshot store")
+		fmt.Println("       kollect diff <run-a> <run-b>")
+		fmt.Println("                         Report resources added, removed and changed between two runs")
+		fmt.Println("       kollect drift --baseline <file>")
+		fmt.Println("                         Collect live data and report drift from a baseline, exiting 2 when it exceeds the allow-list")
+		fmt.Println("       kollect serve --interval 15m")
+		fmt.Println("                         Serve the web interface, re-collecting on a schedule")
 		fmt.Println("Flags:")
 		flag.PrintDefaults()
 		fmt.Println("\nTo pretty-print JSON output, you can use `jq`:")
@@ -43,38 +103,37 @@ func main() {
 		return
 	}
 
+	if !validFormat(*format) {
+		log.Fatalf("Unsupported format %q, expected json, csv or xlsx", *format)
+	}
+	if *format != formatJSON && *output == "" {
+		log.Fatalf("--format %s needs --output", *format)
+	}
+
 	ctx := context.Background()
 
-	// Don't fail if kubeconfig is missing
-	if *kubeconfig == "" {
-		*kubeconfig = os.Getenv("KUBECONFIG")
+	cfg, names, err := collectOpts.config()
+	if err != nil {
+		log.Fatal(err)
 	}
-
-	// Collect data based on inventory type
-	var err error
-	switch *inventoryType {
-	case "aws":
-		data, err = aws.CollectAWSData(ctx)
-	case "azure":
-		data, err = azure.CollectAzureData(ctx)
-	case "kubernetes":
-		data, err = collectData(ctx, *storageOnly, *kubeconfig)
-	case "veeam":
-		if *baseURL == "" || *username == "" || *password == "" {
-			log.Fatal("Veeam URL, username, and password must be provided for Veeam inventory")
-		}
-		data, err = veeam.CollectVeeamData(ctx, *baseURL, *username, *password)
-	default:
-		log.Fatalf("Unsupported inventory type: %s", *inventoryType)
+	doc, err := collectData(ctx, names, cfg)
+	if err != nil {
+		log.Fatal(err)
 	}
+	setData(doc)
 
-	if err != nil {
-		log.Printf("Warning: Error collecting data: %v", err)
-		data = struct{}{}
+	if *storeDir != "" {
+		if err := saveRun(*storeDir, doc); err != nil {
+			log.Printf("Warning: Error saving run to snapshot store: %v", err)
+		}
 	}
 
 	if *output != "" {
-		err = saveToFile(data, *output)
+		if *format == formatJSON {
+			err = saveToFile(doc, *output)
+		} else {
+			err = exportFile(doc, *output, *format)
+		}
 		if err != nil {
 			log.Printf("Warning: Error saving data to file: %v", err)
 		} else {
@@ -83,40 +142,144 @@ func main() {
 	}
 
 	if *browser {
-		startWebServer(data, *baseURL, *username, *password)
+		startWebServer(cfg, *storeDir, nil)
+	} else if *readiness {
+		printReadiness(doc)
+	} else if *table {
+		printTables(doc)
 	} else {
-		printData(data)
+		printData(doc)
 	}
 }
 
-func collectData(ctx context.Context, storageOnly bool, kubeconfig string) (interface{}, error) {
-	data := struct {
-		Kubernetes interface{} `json:"kubernetes,omitempty"`
-		AWS        interface{} `json:"aws,omitempty"`
-		Azure      interface{} `json:"azure,omitempty"`
-		Veeam      interface{} `json:"veeam,omitempty"`
-	}{}
-
-	// Even if kubeconfig is empty, return the empty structure
-	if kubeconfig != "" {
-		if storageOnly {
-			k8sData, err := kollect.CollectStorageData(ctx, kubeconfig)
-			if err == nil {
-				data.Kubernetes = k8sData
-			} else {
-				log.Printf("Warning: Could not collect Kubernetes data: %v", err)
-			}
-		} else {
-			k8sData, err := kollect.CollectData(ctx, kubeconfig)
-			if err == nil {
-				data.Kubernetes = k8sData
-			} else {
-				log.Printf("Warning: Could not collect Kubernetes data: %v", err)
-			}
+// collectFlags are the flags that configure collection. They are shared by
+// the default command and the subcommands that collect live data.
+type collectFlags struct {
+	inventory         *string
+	storageOnly       *bool
+	kubeconfig        *string
+	contexts          stringList
+	allContexts       *bool
+	namespaces        stringList
+	excludeNamespaces stringList
+	selector          *string
+	snapshotMaxAge    *time.Duration
+	crdGroups         *string
+	pageSize          *int64
+	timeout           *time.Duration
+	workers           *int
+	awsRegions        *string
+	awsWorkers        *int
+	awsProfiles       stringList
+	awsAssumeRole     *string
+	awsAccounts       stringList
+	awsSnapshotMaxAge *time.Duration
+	veeamURL          *string
+	veeamUsername     *string
+	veeamPassword     *string
+}
+
+func addCollectFlags(fs *flag.FlagSet) *collectFlags {
+	f := &collectFlags{}
+	f.inventory = fs.String("inventory", "kubernetes", fmt.Sprintf("Comma separated inventories to collect (%s or all)", strings.Join(collector.Names(), "/")))
+	f.storageOnly = fs.Bool("storage", false, "Collect only storage-related objects (Kubernetes Only)")
+	f.kubeconfig = fs.String("kubeconfig", filepath.Join(os.Getenv("HOME"), ".kube", "config"), "Path to the kubeconfig file")
+	fs.Var(&f.contexts, "context", "Kubeconfig context to collect (repeatable)")
+	f.allContexts = fs.Bool("all-contexts", false, "Collect every context in the kubeconfig")
+	fs.Var(&f.namespaces, "namespace", "Namespace to collect (repeatable, default all)")
+	fs.Var(&f.excludeNamespaces, "exclude-namespace", "Namespace to skip (repeatable)")
+	f.selector = fs.String("selector", "", "Label selector applied to namespaced Kubernetes resources")
+	f.snapshotMaxAge = fs.Duration("snapshot-max-age", kollect.DefaultSnapshotMaxAge, "Age after which a claim's newest VolumeSnapshot is reported as stale")
+	f.crdGroups = fs.String("crd-groups", strings.Join(kollect.DefaultCRDGroups, ","), "Comma separated API groups whose custom resources are collected, each with its subgroups (kio.kasten.io matches config.kio.kasten.io)")
+	f.pageSize = fs.Int64("k8s-page-size", kollect.DefaultPageSize, "Number of Kubernetes objects requested per List call (0 disables paging)")
+	f.timeout = fs.Duration("k8s-timeout", kollect.DefaultTimeout, "Timeout for each Kubernetes List call")
+	f.workers = fs.Int("k8s-workers", kollect.DefaultWorkers, "Number of Kubernetes resource kinds fetched concurrently")
+	f.awsRegions = fs.String("aws-regions", "", "Comma separated AWS regions to collect (default every enabled region)")
+	f.awsWorkers = fs.Int("aws-workers", aws.DefaultWorkers, "Number of AWS region and service pairs fetched concurrently")
+	fs.Var(&f.awsProfiles, "aws-profile", "AWS shared config profile to collect as its own account (repeatable)")
+	f.awsAssumeRole = fs.String("aws-assume-role", "", "AWS role ARN template assumed in each --aws-account, e.g. arn:aws:iam::"+aws.AccountPlaceholder+":role/Inventory")
+	fs.Var(&f.awsAccounts, "aws-account", "AWS account ID to collect through --aws-assume-role (repeatable)")
+	f.awsSnapshotMaxAge = fs.Duration("aws-snapshot-max-age", aws.DefaultSnapshotMaxAge, "Age after which an EBS volume's newest snapshot is flagged as stale (0 disables)")
+	f.veeamURL = fs.String("veeam-url", "", "Veeam server URL")
+	f.veeamUsername = fs.String("veeam-username", "", "Veeam username")
+	f.veeamPassword = fs.String("veeam-password", "", "Veeam password")
+	return f
+}
+
+// config returns the collector configuration and the selected inventories.
+func (f *collectFlags) config() (collector.Config, []string, error) {
+	// Don't fail if kubeconfig is missing
+	kubeconfig := *f.kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+
+	cfg := collector.Config{
+		"kubeconfig":           kubeconfig,
+		"storage":              strconv.FormatBool(*f.storageOnly),
+		"k8s-page-size":        strconv.FormatInt(*f.pageSize, 10),
+		"k8s-timeout":          f.timeout.String(),
+		"k8s-workers":          strconv.Itoa(*f.workers),
+		"context":              f.contexts.String(),
+		"all-contexts":         strconv.FormatBool(*f.allContexts),
+		"namespace":            f.namespaces.String(),
+		"exclude-namespace":    f.excludeNamespaces.String(),
+		"selector":             *f.selector,
+		"snapshot-max-age":     f.snapshotMaxAge.String(),
+		"crd-groups":           *f.crdGroups,
+		"aws-regions":          *f.awsRegions,
+		"aws-workers":          strconv.Itoa(*f.awsWorkers),
+		"aws-profile":          f.awsProfiles.String(),
+		"aws-assume-role":      *f.awsAssumeRole,
+		"aws-account":          f.awsAccounts.String(),
+		"aws-snapshot-max-age": f.awsSnapshotMaxAge.String(),
+		"veeam-url":            *f.veeamURL,
+		"veeam-username":       *f.veeamUsername,
+		"veeam-password":       *f.veeamPassword,
+	}
+
+	// Collect data based on inventory type
+	names, err := collector.ParseNames(*f.inventory)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, names, nil
+}
+
+// stringList is a flag.Value that collects repeated flags, also accepting
+// comma separated values.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
 		}
 	}
+	return nil
+}
+
+func collectData(ctx context.Context, names []string, cfg collector.Config) (collector.Document, error) {
+	collectors := make([]collector.Collector, 0, len(names))
+	for _, name := range names {
+		c, err := collector.New(name, cfg)
+		if err != nil {
+			return collector.Document{}, err
+		}
+		collectors = append(collectors, c)
+	}
 
-	return data, nil
+	doc := collector.Run(ctx, collectors)
+	for name, source := range doc.Sources {
+		if source.Error != "" {
+			log.Printf("Warning: Could not collect %s data: %s", name, source.Error)
+		}
+	}
+	return doc, nil
 }
 
 func saveToFile(data interface{}, filename string) error {
@@ -135,6 +298,30 @@ func saveToFile(data interface{}, filename string) error {
 	return err
 }
 
+// saveRun adds doc to the snapshot store in dir.
+func saveRun(dir string, doc collector.Document) error {
+	store, err := history.Open(dir)
+	if err != nil {
+		return err
+	}
+	run, err := store.Save(doc)
+	if err != nil {
+		return err
+	}
+	log.Printf("Saved run %s to %s", run.ID, dir)
+	return nil
+}
+
+// writeSchema writes the JSON Schema describing kollect's output.
+func writeSchema(w io.Writer) error {
+	schema, err := json.MarshalIndent(collector.DocumentSchema(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(schema))
+	return err
+}
+
 func printData(data interface{}) {
 	prettyData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -143,21 +330,50 @@ func printData(data interface{}) {
 	fmt.Println(string(prettyData))
 }
 
-func startWebServer(data interface{}, baseURL, username, password string) {
-	// Initialize empty data structure if nil
-	if data == nil {
-		data = struct {
-			Kubernetes interface{} `json:"kubernetes,omitempty"`
-			AWS        interface{} `json:"aws,omitempty"`
-			Azure      interface{} `json:"azure,omitempty"`
-			Veeam      interface{} `json:"veeam,omitempty"`
-		}{
-			Kubernetes: nil,
-			AWS:        nil,
-			Azure:      nil,
-			Veeam:      nil,
+// printTables prints every collected resource type as a table.
+func printTables(doc collector.Document) {
+	tables, err := export.Tables(doc)
+	if err != nil {
+		log.Fatalf("Error formatting data: %v", err)
+	}
+	if err := writeTables(os.Stdout, tables, time.Now()); err != nil {
+		log.Fatalf("Error writing tables: %v", err)
+	}
+}
+
+// printReadiness prints the backup-readiness report of every collected
+// Kubernetes cluster.
+func printReadiness(doc collector.Document) {
+	switch inventory := doc.Inventories["kubernetes"].(type) {
+	case k8sdata.K8sData:
+		kollect.WriteReadinessTable(os.Stdout, inventory.Readiness)
+	case k8sdata.MultiClusterData:
+		names := make([]string, 0, len(inventory.Clusters))
+		for name := range inventory.Clusters {
+			names = append(names, name)
 		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("Cluster %s\n", name)
+			kollect.WriteReadinessTable(os.Stdout, inventory.Clusters[name].Readiness)
+			fmt.Println()
+		}
+	default:
+		fmt.Println("No Kubernetes data collected")
+	}
+}
+
+// startWebServer serves the web interface and the API over the data global.
+// sched is the scheduler refreshing data in serve mode, or nil.
+func startWebServer(cfg collector.Config, storeDir string, sched *collector.Scheduler) {
+	// Initialize empty data structure if nil. The scheduler may already be
+	// publishing, so check and set under one lock; an empty document has no
+	// counts to compute.
+	dataMutex.Lock()
+	if data == nil {
+		data = collector.Document{}
 	}
+	dataMutex.Unlock()
 
 	// Check if web directory exists
 	webDir := "web"
@@ -194,6 +410,15 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 		}
 	})
 
+	http.HandleFunc("/api/export", serveExport)
+
+	http.HandleFunc("/api/schema", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/schema+json")
+		if err := writeSchema(w); err != nil {
+			log.Printf("Error encoding schema: %v", err)
+		}
+	})
+
 	http.HandleFunc("/api/import", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
@@ -206,9 +431,7 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		dataMutex.Lock()
-		data = importedData
-		dataMutex.Unlock()
+		setData(importedData)
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(map[string]string{"status": "success"})
 		if err != nil {
@@ -217,34 +440,38 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 	})
 
 	http.HandleFunc("/api/switch", func(w http.ResponseWriter, r *http.Request) {
+		if sched != nil {
+			serveScheduledSwitch(w, r, sched)
+			return
+		}
 		inventoryType := r.URL.Query().Get("type")
-		ctx := context.Background()
-		var err error
-		switch inventoryType {
-		case "aws":
-			data, err = aws.CollectAWSData(ctx)
-		case "azure":
-			data, err = azure.CollectAzureData(ctx)
-		case "kubernetes":
-			data, err = collectData(ctx, false, filepath.Join(os.Getenv("HOME"), ".kube", "config"))
-		case "google":
+		if inventoryType == "google" {
 			// Placeholder for Google Cloud data collection
-			data = map[string]string{"message": "Google Cloud data collection not implemented yet"}
-		case "veeam":
-			if baseURL == "" || username == "" || password == "" {
-				http.Error(w, "Veeam URL, username, and password must be provided", http.StatusBadRequest)
-				return
-			}
-			data, err = veeam.CollectVeeamData(ctx, baseURL, username, password)
-		default:
+			setData(map[string]string{"message": "Google Cloud data collection not implemented yet"})
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+			return
+		}
+		names, err := collector.ParseNames(inventoryType)
+		if err != nil {
 			http.Error(w, "Invalid inventory type", http.StatusBadRequest)
 			return
 		}
+		collected, err := collectData(context.Background(), names, cfg)
+		if err == nil && len(collected.Inventories) == 0 {
+			err = fmt.Errorf("%s", collected.Sources[names[0]].Error)
+		}
 		if err != nil {
 			log.Printf("Error collecting data: %v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if storeDir != "" {
+			if err := saveRun(storeDir, collected); err != nil {
+				log.Printf("Warning: Error saving run to snapshot store: %v", err)
+			}
+		}
+		setData(collected)
 		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(map[string]string{"status": "success"})
 		if err != nil {
@@ -252,22 +479,100 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 		}
 	})
 
+	http.HandleFunc("/api/runs", func(w http.ResponseWriter, r *http.Request) {
+		if storeDir == "" {
+			http.Error(w, "No snapshot store configured", http.StatusNotFound)
+			return
+		}
+		store, err := history.Open(storeDir)
+		if err == nil {
+			var runs []history.Run
+			if runs, err = store.List(); err == nil {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(runs)
+				return
+			}
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	})
+
+	http.HandleFunc("/api/diff", func(w http.ResponseWriter, r *http.Request) {
+		if storeDir == "" {
+			http.Error(w, "No snapshot store configured", http.StatusNotFound)
+			return
+		}
+		store, err := history.Open(storeDir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var docs [2]collector.Document
+		for i, ref := range []string{r.URL.Query().Get("from"), r.URL.Query().Get("to")} {
+			id, err := store.Resolve(ref)
+			if err == nil {
+				docs[i], err = store.Load(id)
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		diff, err := history.Compare(docs[0], docs[1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(diff)
+	})
+
+	http.HandleFunc("/api/sources", func(w http.ResponseWriter, r *http.Request) {
+		if sched == nil {
+			http.Error(w, "Scheduled collection is not enabled, run kollect serve", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sched.Status())
+	})
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		dataMutex.Lock()
+		doc, _ := data.(collector.Document)
+		counts := dataCounts
+		dataMutex.Unlock()
+		status := collector.DocumentStatus(doc)
+		if sched != nil {
+			status = sched.Status()
+		}
+		w.Header().Set("Content-Type", metrics.ContentType)
+		if err := metrics.Write(w, counts, status); err != nil {
+			log.Printf("Error writing metrics: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
 	http.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(checkConnections(r.Context(), cfg))
+	})
+
+	http.HandleFunc("/api/collectors", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
 
-		status := struct {
-			AWS        bool `json:"aws"`
-			Azure      bool `json:"azure"`
-			Kubernetes bool `json:"kubernetes"`
-			Veeam      bool `json:"veeam"`
-		}{
-			AWS:        checkAWSConnection(),
-			Azure:      checkAzureConnection(),
-			Kubernetes: checkKubernetesConnection(),
-			Veeam:      checkVeeamConnection(),
+		type collectorInfo struct {
+			Name   string            `json:"name"`
+			Schema []collector.Field `json:"schema"`
+		}
+		var collectors []collectorInfo
+		for _, name := range collector.Names() {
+			c, err := collector.New(name, cfg)
+			if err != nil {
+				continue
+			}
+			collectors = append(collectors, collectorInfo{Name: name, Schema: c.Schema()})
 		}
 
-		json.NewEncoder(w).Encode(status)
+		json.NewEncoder(w).Encode(collectors)
 	})
 
 	http.HandleFunc("/api/configure/aws", func(w http.ResponseWriter, r *http.Request) {
@@ -286,9 +591,10 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 			return
 		}
 
-		// Set AWS credentials
-		if err := aws.ConfigureCredentials(creds.AccessKeyID, creds.SecretAccessKey); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		// Validate the key pair with STS and use it for later collections
+		identity, err := aws.ConfigureCredentials(r.Context(), creds.AccessKeyID, creds.SecretAccessKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
 
@@ -296,6 +602,9 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 		json.NewEncoder(w).Encode(map[string]string{
 			"message": "AWS credentials configured successfully",
 			"status":  "success",
+			"account": identity.Account,
+			"arn":     identity.ARN,
+			"userID":  identity.UserID,
 		})
 	})
 
@@ -370,31 +679,27 @@ func startWebServer(data interface{}, baseURL, username, password string) {
 	}
 }
 
-func checkAWSConnection() bool {
-	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	_, err := aws.GetCredentials(context.Background(), accessKey, secretKey)
-	return err == nil
-}
-
-func checkAzureConnection() bool {
-	tenantID := os.Getenv("AZURE_TENANT_ID")
-	clientID := os.Getenv("AZURE_CLIENT_ID")
-	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
-	_, err := azure.CheckCredentials(context.Background(), tenantID, clientID, clientSecret)
-	return err == nil
-}
-
-func checkKubernetesConnection() bool {
-	kubeconfig := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-	_, err := kollect.CollectData(context.Background(), kubeconfig)
-	return err == nil
-}
-
-func checkVeeamConnection() bool {
-	baseURL := os.Getenv("VEEAM_URL")
-	username := os.Getenv("VEEAM_USERNAME")
-	password := os.Getenv("VEEAM_PASSWORD")
-	_, err := veeam.CollectVeeamData(context.Background(), baseURL, username, password)
-	return err == nil
+// checkConnections runs Check on every registered collector and reports
+// which platforms are reachable.
+func checkConnections(ctx context.Context, cfg collector.Config) map[string]bool {
+	names := collector.Names()
+	status := make(map[string]bool, len(names))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		c, err := collector.New(name, cfg)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(c collector.Collector) {
+			defer wg.Done()
+			err := c.Check(ctx)
+			mu.Lock()
+			status[c.Name()] = err == nil
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+	return status
 }
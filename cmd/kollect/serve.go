@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// DefaultInterval is the time between scheduled collections in serve mode.
+const DefaultInterval = 15 * time.Minute
+
+// runServe implements "kollect serve": the web interface with every
+// selected collector re-run on its own schedule.
+func runServe(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	collectOpts := addCollectFlags(fs)
+	interval := fs.Duration("interval", DefaultInterval, "Time between collections")
+	var sourceIntervals stringList
+	fs.Var(&sourceIntervals, "source-interval", "Interval for one inventory as name=duration, e.g. aws=1h (repeatable)")
+	jitter := fs.Float64("jitter", 0.1, "Fraction of the interval by which each run is randomly moved earlier or later")
+	storeDir := fs.String("store", os.Getenv(storeEnv), "Snapshot store directory every run is saved to (default $"+storeEnv+")")
+	fs.Parse(args)
+
+	if *interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if *jitter < 0 || *jitter >= 1 {
+		return fmt.Errorf("--jitter must be at least 0 and less than 1")
+	}
+	cfg, names, err := collectOpts.config()
+	if err != nil {
+		return err
+	}
+	intervals, err := parseIntervals(sourceIntervals, names)
+	if err != nil {
+		return err
+	}
+	collectors := make([]collector.Collector, 0, len(names))
+	for _, name := range names {
+		c, err := collector.New(name, cfg)
+		if err != nil {
+			return err
+		}
+		collectors = append(collectors, c)
+	}
+
+	sched := &collector.Scheduler{
+		Collectors: collectors,
+		Interval:   *interval,
+		Intervals:  intervals,
+		Jitter:     *jitter,
+		OnUpdate: func(doc collector.Document) {
+			setData(doc)
+			// Documents from before every source has run once would
+			// show up in diffs as resources appearing.
+			if *storeDir != "" && len(doc.Sources) == len(names) {
+				if err := saveRun(*storeDir, doc); err != nil {
+					log.Printf("Warning: Error saving run to snapshot store: %v", err)
+				}
+			}
+		},
+	}
+	for _, name := range names {
+		every := *interval
+		if d, ok := intervals[name]; ok {
+			every = d
+		}
+		log.Printf("Collecting %s every %s", name, every)
+	}
+	go sched.Run(context.Background())
+	startWebServer(cfg, *storeDir, sched)
+	return nil
+}
+
+// serveScheduledSwitch handles /api/switch in serve mode. The scheduler
+// owns the served data, so the selected inventories are collected through
+// it instead of replacing the data until its next run.
+func serveScheduledSwitch(w http.ResponseWriter, r *http.Request, sched *collector.Scheduler) {
+	names, err := collector.ParseNames(r.URL.Query().Get("type"))
+	if err != nil {
+		http.Error(w, "Invalid inventory type", http.StatusBadRequest)
+		return
+	}
+	for _, name := range names {
+		if _, ok := sched.Status()[name]; !ok {
+			http.Error(w, fmt.Sprintf("%s is not collected by kollect serve", name), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := sched.Collect(r.Context(), names); err != nil {
+		log.Printf("Error collecting data: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status := sched.Status()
+	for _, name := range names {
+		if status[name].Status == collector.StatusError {
+			http.Error(w, status[name].Error, http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "success"}); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
+
+// parseIntervals reads name=duration pairs for the selected inventories.
+func parseIntervals(pairs []string, names []string) (map[string]time.Duration, error) {
+	selected := map[string]bool{}
+	for _, name := range names {
+		selected[name] = true
+	}
+	intervals := map[string]time.Duration{}
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --source-interval %q, expected name=duration", pair)
+		}
+		if !selected[name] {
+			return nil, fmt.Errorf("--source-interval %q names an inventory that is not collected", pair)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid --source-interval %q, expected a positive duration", pair)
+		}
+		intervals[name] = d
+	}
+	return intervals, nil
+}
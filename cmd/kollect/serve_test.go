@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// switchCollector returns the number of its run as the inventory and
+// fails its third run.
+type switchCollector struct {
+	mu   sync.Mutex
+	runs int
+}
+
+func (c *switchCollector) Name() string              { return "switchtest" }
+func (c *switchCollector) Schema() []collector.Field { return nil }
+func (c *switchCollector) Check(context.Context) error {
+	return nil
+}
+
+func (c *switchCollector) Collect(context.Context) (collector.Inventory, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runs++
+	if c.runs == 3 {
+		return nil, errors.New("unreachable")
+	}
+	return c.runs, nil
+}
+
+func TestServeScheduledSwitch(t *testing.T) {
+	c := &switchCollector{}
+	collector.Register(c.Name(), func(collector.Config) collector.Collector { return c })
+	sched := &collector.Scheduler{
+		Collectors: []collector.Collector{c},
+		Interval:   time.Hour,
+		OnUpdate:   func(doc collector.Document) { setData(doc) },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sched.Run(ctx)
+	deadline := time.Now().Add(5 * time.Second)
+	for sched.Status()["switchtest"].Runs == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the first run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	switchTo := func(inventory string) int {
+		rec := httptest.NewRecorder()
+		serveScheduledSwitch(rec, httptest.NewRequest(http.MethodGet, "/api/switch?type="+inventory, nil), sched)
+		return rec.Code
+	}
+
+	if code := switchTo("switchtest"); code != http.StatusOK {
+		t.Fatalf("switch returned %d", code)
+	}
+	// The served data is the scheduler's document with the new run.
+	dataMutex.Lock()
+	doc, ok := data.(collector.Document)
+	dataMutex.Unlock()
+	if !ok || doc.Inventories["switchtest"] != 2 {
+		t.Errorf("served inventory = %v, want the second run", doc.Inventories["switchtest"])
+	}
+	if runs := sched.Status()["switchtest"].Runs; runs != 2 {
+		t.Errorf("scheduler counted %d runs, want 2", runs)
+	}
+
+	if code := switchTo("switchtest"); code != http.StatusInternalServerError {
+		t.Errorf("failed collection returned %d", code)
+	}
+	dataMutex.Lock()
+	doc, _ = data.(collector.Document)
+	dataMutex.Unlock()
+	if doc.Inventories["switchtest"] != 2 {
+		t.Errorf("failed collection replaced the inventory with %v", doc.Inventories["switchtest"])
+	}
+
+	if code := switchTo("aws"); code != http.StatusBadRequest {
+		t.Errorf("unscheduled inventory returned %d", code)
+	}
+	if code := switchTo("nosuchinventory"); code != http.StatusBadRequest {
+		t.Errorf("unknown inventory returned %d", code)
+	}
+}
+
+func TestParseIntervals(t *testing.T) {
+	names := []string{"aws", "kubernetes"}
+	intervals, err := parseIntervals([]string{"aws=1h", "kubernetes=90s"}, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intervals["aws"] != time.Hour || intervals["kubernetes"] != 90*time.Second {
+		t.Errorf("parseIntervals = %v", intervals)
+	}
+	for _, pair := range []string{"aws", "veeam=1h", "aws=soon", "aws=0s", "aws=-1m"} {
+		if _, err := parseIntervals([]string{pair}, names); err == nil {
+			t.Errorf("parseIntervals accepted %q", pair)
+		}
+	}
+}
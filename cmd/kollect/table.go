@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/export"
+)
+
+// ageColumn is replaced by an AGE column computed when the table is
+// printed, so ages are never stored.
+const ageColumn = "creationTimestamp"
+
+// writeTables prints one aligned table per resource type, with ages
+// relative to now.
+func writeTables(w io.Writer, tables []export.Table, now time.Time) error {
+	if len(tables) == 0 {
+		_, err := fmt.Fprintln(w, "No resources collected")
+		return err
+	}
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, t.Name())
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		header := make([]string, len(t.Columns))
+		for j, column := range t.Columns {
+			if column == ageColumn {
+				column = "age"
+			}
+			header[j] = strings.ToUpper(column)
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			for j, value := range row {
+				if t.Columns[j] == ageColumn {
+					value = formatAge(value, now)
+				}
+				if value == "" {
+					value = "-"
+				}
+				cells[j] = value
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatAge formats the time since an RFC3339 timestamp like the web
+// interface does, e.g. "3d4h5m". Unparseable timestamps give "".
+func formatAge(timestamp string, now time.Time) string {
+	created, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+	minutes := int(now.Sub(created).Minutes())
+	if minutes < 0 {
+		minutes = 0
+	}
+	return fmt.Sprintf("%dd%dh%dm", minutes/1440, minutes%1440/60, minutes%60)
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// roleSessionName names the sessions kollect opens when assuming roles, so
+// they are recognisable in CloudTrail.
+const roleSessionName = "kollect"
+
+// account is one set of credentials to collect with. key identifies the
+// account until its caller identity is known: the account ID for assumed
+// roles, the profile name for profiles.
+type account struct {
+	key  string
+	load func(ctx context.Context) (aws.Config, error)
+}
+
+// accounts returns an account per profile and per account ID the role
+// template is applied to. Roles are assumed with the credentials
+// loadConfig returns.
+func accounts(opts Options) ([]account, error) {
+	if len(opts.AccountIDs) > 0 && opts.AssumeRole == "" {
+		return nil, fmt.Errorf("AWS account IDs need a role to assume")
+	}
+	if opts.AssumeRole != "" && len(opts.AccountIDs) == 0 {
+		return nil, fmt.Errorf("AWS assume-role template %q needs at least one account ID", opts.AssumeRole)
+	}
+	if len(opts.AccountIDs) > 1 && !strings.Contains(opts.AssumeRole, AccountPlaceholder) {
+		return nil, fmt.Errorf("AWS assume-role template %q does not contain %s", opts.AssumeRole, AccountPlaceholder)
+	}
+
+	var list []account
+	for _, profile := range opts.Profiles {
+		profile := profile
+		list = append(list, account{key: profile, load: func(ctx context.Context) (aws.Config, error) {
+			cfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profile))
+			if err != nil {
+				return cfg, fmt.Errorf("unable to load profile %s, %v", profile, err)
+			}
+			return cfg, nil
+		}})
+	}
+	for _, id := range opts.AccountIDs {
+		roleARN := strings.ReplaceAll(opts.AssumeRole, AccountPlaceholder, id)
+		list = append(list, account{key: id, load: func(ctx context.Context) (aws.Config, error) {
+			cfg, err := loadConfig(ctx, opts)
+			if err != nil {
+				return cfg, err
+			}
+			provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN, func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = roleSessionName
+			})
+			cfg.Credentials = aws.NewCredentialsCache(provider)
+			return cfg, nil
+		}})
+	}
+	return list, nil
+}
+
+// collectAccounts collects every profile and assumed role in parallel into
+// Accounts, keyed by the account ID STS reports. An account that cannot be
+// reached is still returned, with the failure in its Errors; an error is
+// only returned if no account could be collected.
+func collectAccounts(ctx context.Context, opts Options) (AWSData, error) {
+	list, err := accounts(opts)
+	if err != nil {
+		return AWSData{}, err
+	}
+	results := make([]AWSData, len(list))
+	errs := make([]error, len(list))
+	var wg sync.WaitGroup
+	for i, a := range list {
+		wg.Add(1)
+		go func(i int, a account) {
+			defer wg.Done()
+			cfg, err := a.load(ctx)
+			if err != nil {
+				errs[i] = err
+			} else {
+				results[i], errs[i] = collectAccount(ctx, cfg, opts)
+			}
+			if errs[i] != nil && len(results[i].Errors) == 0 {
+				results[i].Errors = append(results[i].Errors, CollectionError{Service: "account", Reason: errs[i].Error()})
+			}
+		}(i, a)
+	}
+	wg.Wait()
+
+	// Add accounts in configuration order so a duplicate keeps the first.
+	data := AWSData{Accounts: make(map[string]AWSData, len(list))}
+	failed := 0
+	for i, a := range list {
+		if errs[i] != nil {
+			failed++
+		}
+		key := a.key
+		if identity := results[i].Identity; identity != nil && identity.Account != "" {
+			key = identity.Account
+		}
+		if _, dup := data.Accounts[key]; dup {
+			log.Printf("Warning: skipping %s, account %s is already collected", a.key, key)
+			continue
+		}
+		data.Accounts[key] = results[i]
+	}
+	if failed == len(list) {
+		return data, fmt.Errorf("could not collect any of %d AWS accounts", len(list))
+	}
+	return data, nil
+}
+
+// checkAccounts calls STS GetCallerIdentity with the credentials
+// CollectAWSData would use, failing only if no account can be reached.
+func checkAccounts(ctx context.Context, opts Options) error {
+	list := []account{{load: func(ctx context.Context) (aws.Config, error) {
+		return loadConfig(ctx, opts)
+	}}}
+	if opts.multiAccount() {
+		var err error
+		if list, err = accounts(opts); err != nil {
+			return err
+		}
+	}
+	errs := make([]error, len(list))
+	var wg sync.WaitGroup
+	for i, a := range list {
+		wg.Add(1)
+		go func(i int, a account) {
+			defer wg.Done()
+			cfg, err := a.load(ctx)
+			if err == nil {
+				_, err = fetchCallerIdentity(ctx, sts.NewFromConfig(cfg))
+			}
+			errs[i] = err
+		}(i, a)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errs[0]
+}
+
+// fetchCallerIdentity returns the principal the client's credentials
+// belong to.
+func fetchCallerIdentity(ctx context.Context, client *sts.Client) (*CallerIdentity, error) {
+	output, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get caller identity, %v", err)
+	}
+	return &CallerIdentity{
+		Account: aws.ToString(output.Account),
+		ARN:     aws.ToString(output.Arn),
+		UserID:  aws.ToString(output.UserId),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+func init() {
+	collector.Register("aws", NewCollector)
+}
+
+var collectorSchema = []collector.Field{
+	{Name: "aws-access-key-id", Description: "AWS access key ID (default the shared credential chain)", Env: "AWS_ACCESS_KEY_ID"},
+	{Name: "aws-secret-access-key", Description: "AWS secret access key", Env: "AWS_SECRET_ACCESS_KEY", Secret: true},
+	{Name: "aws-session-token", Description: "AWS session token for temporary credentials", Env: "AWS_SESSION_TOKEN", Secret: true},
+	{Name: "aws-regions", Description: "Comma separated regions to collect (default every enabled region)"},
+	{Name: "aws-workers", Description: "Number of region and service pairs fetched concurrently", Default: strconv.Itoa(DefaultWorkers)},
+	{Name: "aws-profile", Description: "Comma separated shared config profiles, each collected as its own account"},
+	{Name: "aws-assume-role", Description: "Role ARN template assumed in each aws-account, e.g. arn:aws:iam::" + AccountPlaceholder + ":role/Inventory"},
+	{Name: "aws-account", Description: "Comma separated account IDs the aws-assume-role template is applied to"},
+	{Name: "aws-snapshot-max-age", Description: "Age after which an EBS volume's newest snapshot is flagged as stale (0 disables)", Default: DefaultSnapshotMaxAge.String()},
+}
+
+// Collector collects AWS inventory through the collector registry.
+type Collector struct {
+	cfg  collector.Config
+	opts Options
+}
+
+// NewCollector returns an AWS collector configured from cfg.
+func NewCollector(cfg collector.Config) collector.Collector {
+	cfg = cfg.Resolve(collectorSchema)
+	opts := DefaultOptions()
+	opts.AccessKeyID = cfg["aws-access-key-id"]
+	opts.SecretAccessKey = cfg["aws-secret-access-key"]
+	opts.SessionToken = cfg["aws-session-token"]
+	opts.Regions = cfg.List("aws-regions")
+	opts.Profiles = cfg.List("aws-profile")
+	opts.AssumeRole = cfg["aws-assume-role"]
+	opts.AccountIDs = cfg.List("aws-account")
+	if d, err := time.ParseDuration(cfg["aws-snapshot-max-age"]); err == nil && d >= 0 {
+		opts.SnapshotMaxAge = d
+	}
+	if n, err := strconv.Atoi(cfg["aws-workers"]); err == nil && n > 0 {
+		opts.Workers = n
+	}
+	return &Collector{cfg: cfg, opts: opts}
+}
+
+func (c *Collector) Name() string {
+	return "aws"
+}
+
+func (c *Collector) Schema() []collector.Field {
+	return collectorSchema
+}
+
+func (c *Collector) InventoryTypes() []collector.Inventory {
+	return []collector.Inventory{AWSData{}}
+}
+
+func (c *Collector) Collect(ctx context.Context) (collector.Inventory, error) {
+	return CollectAWSData(ctx, c.opts)
+}
+
+func (c *Collector) Check(ctx context.Context) error {
+	return checkAccounts(ctx, c.opts)
+}
@@ -3,34 +3,79 @@ package aws
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
-// GetCredentials validates AWS credentials and returns the config and any error
-func GetCredentials(ctx context.Context, accessKey, secretKey string) (interface{}, error) {
+var (
+	credentialsMutex sync.RWMutex
+	// configured replaces the default credential chain once a key pair
+	// has been configured for the session.
+	configured aws.CredentialsProvider
+)
+
+// GetCallerIdentity validates a key pair by calling STS GetCallerIdentity
+// and returns the principal it belongs to.
+func GetCallerIdentity(ctx context.Context, accessKey, secretKey string) (CallerIdentity, error) {
 	if accessKey == "" || secretKey == "" {
-		return nil, fmt.Errorf("AWS access key and secret key are required")
+		return CallerIdentity{}, fmt.Errorf("AWS access key and secret key are required")
 	}
-
-	provider := credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")
-
 	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithCredentialsProvider(provider),
-		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
 	)
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate AWS credentials: %v", err)
+		return CallerIdentity{}, fmt.Errorf("unable to load SDK config, %v", err)
 	}
+	if cfg.Region == "" {
+		cfg.Region = defaultRegion
+	}
+	identity, err := fetchCallerIdentity(ctx, sts.NewFromConfig(cfg))
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to validate AWS credentials: %v", err)
+	}
+	return *identity, nil
+}
 
-	return cfg, nil
+// ConfigureCredentials validates a key pair and uses it instead of the
+// default credential chain for every later collection in this session.
+func ConfigureCredentials(ctx context.Context, accessKey, secretKey string) (CallerIdentity, error) {
+	identity, err := GetCallerIdentity(ctx, accessKey, secretKey)
+	if err != nil {
+		return identity, err
+	}
+	credentialsMutex.Lock()
+	configured = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""))
+	credentialsMutex.Unlock()
+	return identity, nil
 }
 
-// ConfigureCredentials sets up AWS credentials for the session
-func ConfigureCredentials(accessKey, secretKey string) error {
-	ctx := context.Background()
-	_, err := GetCredentials(ctx, accessKey, secretKey)
-	return err
+// loadConfig loads the shared configuration with the credentials set by
+// ConfigureCredentials or, failing that, the key pair in opts, if any, and
+// the default region when none is set.
+func loadConfig(ctx context.Context, opts Options) (aws.Config, error) {
+	credentialsMutex.RLock()
+	provider := configured
+	credentialsMutex.RUnlock()
+	if provider == nil && (opts.AccessKeyID != "" || opts.SecretAccessKey != "") {
+		if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+			return aws.Config{}, fmt.Errorf("AWS access key ID and secret access key must be set together")
+		}
+		provider = credentials.NewStaticCredentialsProvider(opts.AccessKeyID, opts.SecretAccessKey, opts.SessionToken)
+	}
+	var optFns []func(*config.LoadOptions) error
+	if provider != nil {
+		optFns = append(optFns, config.WithCredentialsProvider(provider))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return cfg, fmt.Errorf("unable to load SDK config, %v", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultRegion
+	}
+	return cfg, nil
 }
@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func fetchEBSVolumes(ctx context.Context, client *ec2.Client, region string) ([]EBSVolumeInfo, error) {
+	var volumes []EBSVolumeInfo
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return volumes, fmt.Errorf("unable to describe volumes in region %s, %v", region, err)
+		}
+		for _, volume := range page.Volumes {
+			info := EBSVolumeInfo{
+				VolumeID:          aws.ToString(volume.VolumeId),
+				Type:              string(volume.VolumeType),
+				SizeGiB:           aws.ToInt32(volume.Size),
+				IOPS:              aws.ToInt32(volume.Iops),
+				Encrypted:         aws.ToBool(volume.Encrypted),
+				State:             string(volume.State),
+				AvailabilityZone:  aws.ToString(volume.AvailabilityZone),
+				Region:            region,
+				CreationTimestamp: timestamp(volume.CreateTime),
+			}
+			for _, attachment := range volume.Attachments {
+				if attachment.InstanceId != nil {
+					info.InstanceIDs = append(info.InstanceIDs, *attachment.InstanceId)
+				}
+			}
+			volumes = append(volumes, info)
+		}
+	}
+	return volumes, nil
+}
+
+// fetchEBSSnapshots lists the snapshots owned by the account, leaving out
+// public and shared snapshots of other accounts.
+func fetchEBSSnapshots(ctx context.Context, client *ec2.Client, region string) ([]EBSSnapshotInfo, error) {
+	var snapshots []EBSSnapshotInfo
+	paginator := ec2.NewDescribeSnapshotsPaginator(client, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return snapshots, fmt.Errorf("unable to describe snapshots in region %s, %v", region, err)
+		}
+		for _, snapshot := range page.Snapshots {
+			snapshots = append(snapshots, EBSSnapshotInfo{
+				SnapshotID:        aws.ToString(snapshot.SnapshotId),
+				VolumeID:          aws.ToString(snapshot.VolumeId),
+				SizeGiB:           aws.ToInt32(snapshot.VolumeSize),
+				Encrypted:         aws.ToBool(snapshot.Encrypted),
+				State:             string(snapshot.State),
+				Region:            region,
+				CreationTimestamp: timestamp(snapshot.StartTime),
+			})
+		}
+	}
+	return snapshots, nil
+}
+
+// flagStaleVolumes records the newest completed snapshot of every volume
+// and flags volumes without one newer than maxAge. Volumes in regions
+// whose snapshots could not be listed are left unflagged, and a maxAge of
+// zero disables the flag.
+func flagStaleVolumes(data *AWSData, now time.Time, maxAge time.Duration) {
+	failed := map[string]bool{}
+	for _, e := range data.Errors {
+		if e.Service == "ebs-snapshots" {
+			failed[e.Region] = true
+		}
+	}
+	latest := map[string]time.Time{}
+	for _, snapshot := range data.EBSSnapshots {
+		if snapshot.State != string(ec2types.SnapshotStateCompleted) {
+			continue
+		}
+		started, err := time.Parse(time.RFC3339, snapshot.CreationTimestamp)
+		if err != nil {
+			continue
+		}
+		key := snapshot.Region + "/" + snapshot.VolumeID
+		if started.After(latest[key]) {
+			latest[key] = started
+		}
+	}
+	for i := range data.EBSVolumes {
+		volume := &data.EBSVolumes[i]
+		if failed[volume.Region] {
+			continue
+		}
+		started, ok := latest[volume.Region+"/"+volume.VolumeID]
+		if ok {
+			volume.LatestSnapshot = started.Format(time.RFC3339)
+		}
+		volume.SnapshotStale = maxAge > 0 && (!ok || now.Sub(started) > maxAge)
+	}
+}
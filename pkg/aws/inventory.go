@@ -3,283 +3,488 @@ package aws
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// EC2InstanceInfo describes an instance. CreationTimestamp is its last
+// launch time, the closest EC2 records to a creation time.
 type EC2InstanceInfo struct {
-	Name       string
-	InstanceID string
-	Type       string
-	State      string
-	Region     string
+	Name              string `json:"name"`
+	InstanceID        string `json:"instanceID"`
+	Type              string `json:"type"`
+	State             string `json:"state"`
+	Region            string `json:"region"`
+	CreationTimestamp string `json:"creationTimestamp"`
+	// VolumeIDs are the EBS volumes in the instance's block device
+	// mappings.
+	VolumeIDs []string `json:"volumeIDs,omitempty"`
+}
+
+// EBSVolumeInfo describes an EBS volume. InstanceIDs are the instances it
+// is attached to, more than one for Multi-Attach volumes. LatestSnapshot is
+// the start time of its newest completed snapshot owned by the account,
+// and SnapshotStale is set when there is none within the snapshot max age.
+type EBSVolumeInfo struct {
+	VolumeID          string   `json:"volumeID"`
+	Type              string   `json:"type"`
+	SizeGiB           int32    `json:"sizeGiB"`
+	IOPS              int32    `json:"iops,omitempty"`
+	Encrypted         bool     `json:"encrypted"`
+	State             string   `json:"state"`
+	AvailabilityZone  string   `json:"availabilityZone"`
+	Region            string   `json:"region"`
+	InstanceIDs       []string `json:"instanceIDs,omitempty"`
+	LatestSnapshot    string   `json:"latestSnapshot,omitempty"`
+	SnapshotStale     bool     `json:"snapshotStale"`
+	CreationTimestamp string   `json:"creationTimestamp"`
+}
+
+// EBSSnapshotInfo describes an EBS snapshot owned by the account.
+// CreationTimestamp is when the snapshot started.
+type EBSSnapshotInfo struct {
+	SnapshotID        string `json:"snapshotID"`
+	VolumeID          string `json:"volumeID"`
+	SizeGiB           int32  `json:"sizeGiB"`
+	Encrypted         bool   `json:"encrypted"`
+	State             string `json:"state"`
+	Region            string `json:"region"`
+	CreationTimestamp string `json:"creationTimestamp"`
 }
 
 type S3BucketInfo struct {
-	Name      string
-	Immutable bool
-	Region    string
+	Name              string `json:"name"`
+	Immutable         bool   `json:"immutable"`
+	Region            string `json:"region"`
+	CreationTimestamp string `json:"creationTimestamp"`
 }
 
 type RDSInstanceInfo struct {
-	InstanceID string
-	Engine     string
-	Status     string
-	Region     string
+	InstanceID        string `json:"instanceID"`
+	Engine            string `json:"engine"`
+	Status            string `json:"status"`
+	Region            string `json:"region"`
+	CreationTimestamp string `json:"creationTimestamp"`
 }
 
 type DynamoDBTableInfo struct {
-	TableName string
-	Status    string
-	Region    string
+	TableName         string `json:"tableName"`
+	Status            string `json:"status"`
+	Region            string `json:"region"`
+	CreationTimestamp string `json:"creationTimestamp"`
 }
 
+// VPCInfo describes a VPC. AWS does not record when a VPC was created, so
+// unlike the other types it has no CreationTimestamp.
 type VPCInfo struct {
-	VPCID  string
-	State  string
-	Region string
+	VPCID  string `json:"vpcID"`
+	State  string `json:"state"`
+	Region string `json:"region"`
 }
 
+// CallerIdentity is the principal STS reports for the credentials an
+// account was collected with.
+type CallerIdentity struct {
+	Account string `json:"account"`
+	ARN     string `json:"arn"`
+	UserID  string `json:"userID"`
+}
+
+// AWSData is the inventory of one account, or of several accounts keyed by
+// account ID in Accounts when profiles or assumed roles were configured.
 type AWSData struct {
-	EC2Instances   []EC2InstanceInfo
-	S3Buckets      []S3BucketInfo
-	RDSInstances   []RDSInstanceInfo
-	DynamoDBTables []DynamoDBTableInfo
-	VPCs           []VPCInfo
+	Identity       *CallerIdentity     `json:"identity,omitempty"`
+	EC2Instances   []EC2InstanceInfo   `json:"ec2Instances,omitempty"`
+	EBSVolumes     []EBSVolumeInfo     `json:"ebsVolumes,omitempty"`
+	EBSSnapshots   []EBSSnapshotInfo   `json:"ebsSnapshots,omitempty"`
+	S3Buckets      []S3BucketInfo      `json:"s3Buckets,omitempty"`
+	RDSInstances   []RDSInstanceInfo   `json:"rdsInstances,omitempty"`
+	DynamoDBTables []DynamoDBTableInfo `json:"dynamoDBTables,omitempty"`
+	VPCs           []VPCInfo           `json:"vpcs,omitempty"`
+	Errors         []CollectionError   `json:"errors,omitempty"`
+	Accounts       map[string]AWSData  `json:"accounts,omitempty"`
+}
+
+// defaultRegion is used to discover regions and list S3 buckets when the
+// shared configuration names no region.
+const defaultRegion = "us-east-1"
+
+// CollectionError records a service that could not be listed in a region.
+// Region is empty for global services such as S3.
+type CollectionError struct {
+	Service string `json:"service"`
+	Region  string `json:"region,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// fetchTask lists one service in one region.
+type fetchTask struct {
+	service string
+	region  string
+	fetch   func(ctx context.Context) error
 }
 
-func FetchEC2Instances(ctx context.Context) ([]EC2InstanceInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+// CollectAWSData collects the account of the configured or default
+// credentials, or every configured profile and assumed role into Accounts.
+func CollectAWSData(ctx context.Context, opts Options) (AWSData, error) {
+	if opts.multiAccount() {
+		return collectAccounts(ctx, opts)
+	}
+	cfg, err := loadConfig(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
+		return AWSData{}, err
+	}
+	return collectAccount(ctx, cfg, opts)
+}
+
+// collectAccount records the caller identity of cfg, discovers the
+// account's regions once and then lists EC2 instances, EBS volumes and
+// snapshots, RDS instances, DynamoDB tables and VPCs in every region, and
+// S3 buckets once, fetching up to opts.Workers region and service pairs
+// concurrently. A failing pair is recorded in Errors; collection only
+// fails if every pair does.
+func collectAccount(ctx context.Context, cfg aws.Config, opts Options) (AWSData, error) {
+	if cfg.Region == "" {
+		cfg.Region = defaultRegion
+	}
+
+	var err error
+	regions := opts.Regions
+	if len(regions) == 0 {
+		regions, err = discoverRegions(ctx, ec2.NewFromConfig(cfg))
+		if err != nil {
+			return AWSData{}, err
+		}
 	}
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	var data AWSData
+	var mu sync.Mutex
+	tasks := []fetchTask{
+		{service: "sts", fetch: func(ctx context.Context) error {
+			identity, err := fetchCallerIdentity(ctx, sts.NewFromConfig(cfg))
+			mu.Lock()
+			data.Identity = identity
+			mu.Unlock()
+			return err
+		}},
+		{service: "s3", fetch: func(ctx context.Context) error {
+			buckets, err := fetchS3Buckets(ctx, cfg, regions, len(opts.Regions) > 0)
+			mu.Lock()
+			data.S3Buckets = append(data.S3Buckets, buckets...)
+			mu.Unlock()
+			return err
+		}},
+	}
+	for _, region := range regions {
+		region := region
+		regional := func(o *ec2.Options) { o.Region = region }
+		ec2Client := ec2.NewFromConfig(cfg, regional)
+		tasks = append(tasks,
+			fetchTask{service: "ec2", region: region, fetch: func(ctx context.Context) error {
+				instances, err := fetchEC2Instances(ctx, ec2Client, region)
+				mu.Lock()
+				data.EC2Instances = append(data.EC2Instances, instances...)
+				mu.Unlock()
+				return err
+			}},
+			fetchTask{service: "ebs-volumes", region: region, fetch: func(ctx context.Context) error {
+				volumes, err := fetchEBSVolumes(ctx, ec2Client, region)
+				mu.Lock()
+				data.EBSVolumes = append(data.EBSVolumes, volumes...)
+				mu.Unlock()
+				return err
+			}},
+			fetchTask{service: "ebs-snapshots", region: region, fetch: func(ctx context.Context) error {
+				snapshots, err := fetchEBSSnapshots(ctx, ec2Client, region)
+				mu.Lock()
+				data.EBSSnapshots = append(data.EBSSnapshots, snapshots...)
+				mu.Unlock()
+				return err
+			}},
+			fetchTask{service: "rds", region: region, fetch: func(ctx context.Context) error {
+				client := rds.NewFromConfig(cfg, func(o *rds.Options) { o.Region = region })
+				instances, err := fetchRDSInstances(ctx, client, region)
+				mu.Lock()
+				data.RDSInstances = append(data.RDSInstances, instances...)
+				mu.Unlock()
+				return err
+			}},
+			fetchTask{service: "dynamodb", region: region, fetch: func(ctx context.Context) error {
+				client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) { o.Region = region })
+				tables, err := fetchDynamoDBTables(ctx, client, region)
+				mu.Lock()
+				data.DynamoDBTables = append(data.DynamoDBTables, tables...)
+				mu.Unlock()
+				return err
+			}},
+			fetchTask{service: "vpc", region: region, fetch: func(ctx context.Context) error {
+				vpcs, err := fetchVPCs(ctx, ec2Client, region)
+				mu.Lock()
+				data.VPCs = append(data.VPCs, vpcs...)
+				mu.Unlock()
+				return err
+			}},
+		)
+	}
+
+	err = runFetchTasks(ctx, &data, opts.Workers, tasks)
+	flagStaleVolumes(&data, time.Now(), opts.SnapshotMaxAge)
+	sortData(&data)
+	return data, err
+}
+
+// discoverRegions returns the regions enabled for the account.
+func discoverRegions(ctx context.Context, client *ec2.Client) ([]string, error) {
+	output, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to describe regions, %v", err)
 	}
+	regions := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
 
-	var allInstances []EC2InstanceInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
-		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
+// runFetchTasks runs tasks on a pool of workers and records failures in
+// data.Errors.
+func runFetchTasks(ctx context.Context, data *AWSData, workers int, tasks []fetchTask) error {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, len(tasks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = tasks[i].fetch(ctx)
+			}
+		}()
+	}
+	for i := range tasks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Report errors in task order so output is stable between runs.
+	for i, err := range errs {
+		if err == nil {
+			continue
 		}
+		log.Printf("Warning: could not fetch %s in %s: %v", tasks[i].service, tasks[i].region, err)
+		data.Errors = append(data.Errors, CollectionError{
+			Service: tasks[i].service,
+			Region:  tasks[i].region,
+			Reason:  err.Error(),
+		})
+	}
+	if len(tasks) > 0 && len(data.Errors) == len(tasks) {
+		return fmt.Errorf("error fetching %s: %s", data.Errors[0].Service, data.Errors[0].Reason)
+	}
+	return nil
+}
 
-		regionEc2Client := ec2.NewFromConfig(regionCfg)
-		result, err := regionEc2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
+func fetchEC2Instances(ctx context.Context, client *ec2.Client, region string) ([]EC2InstanceInfo, error) {
+	var instances []EC2InstanceInfo
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to describe instances in region %s, %v", *region.RegionName, err)
+			return instances, fmt.Errorf("unable to describe instances in region %s, %v", region, err)
 		}
-
-		for _, reservation := range result.Reservations {
+		for _, reservation := range page.Reservations {
 			for _, instance := range reservation.Instances {
-				allInstances = append(allInstances, EC2InstanceInfo{
-					Name:       aws.ToString(instance.KeyName),
-					InstanceID: aws.ToString(instance.InstanceId),
-					Type:       string(instance.InstanceType),
-					State:      string(instance.State.Name),
-					Region:     *region.RegionName,
-				})
+				info := EC2InstanceInfo{
+					Name:              aws.ToString(instance.KeyName),
+					InstanceID:        aws.ToString(instance.InstanceId),
+					Type:              string(instance.InstanceType),
+					State:             string(instance.State.Name),
+					Region:            region,
+					CreationTimestamp: timestamp(instance.LaunchTime),
+				}
+				for _, mapping := range instance.BlockDeviceMappings {
+					if mapping.Ebs != nil && mapping.Ebs.VolumeId != nil {
+						info.VolumeIDs = append(info.VolumeIDs, *mapping.Ebs.VolumeId)
+					}
+				}
+				instances = append(instances, info)
 			}
 		}
 	}
-
-	return allInstances, nil
+	return instances, nil
 }
 
-func FetchS3Buckets(ctx context.Context) ([]S3BucketInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
-	}
-
+// fetchS3Buckets lists every bucket once, since the bucket list is global.
+// When only is set, buckets outside regions are left out.
+func fetchS3Buckets(ctx context.Context, cfg aws.Config, regions []string, only bool) ([]S3BucketInfo, error) {
 	svc := s3.NewFromConfig(cfg)
-	result, err := svc.ListBuckets(ctx, &s3.ListBucketsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to list buckets, %v", err)
+	var all []s3types.Bucket
+	paginator := s3.NewListBucketsPaginator(svc, &s3.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list buckets, %v", err)
+		}
+		all = append(all, page.Buckets...)
 	}
 
+	selected := map[string]bool{}
+	for _, region := range regions {
+		selected[region] = true
+	}
 	var buckets []S3BucketInfo
-	for _, bucket := range result.Buckets {
-		region, err := svc.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+	for _, bucket := range all {
+		location, err := svc.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 			Bucket: bucket.Name,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("unable to get bucket location for %s, %v", aws.ToString(bucket.Name), err)
+			return buckets, fmt.Errorf("unable to get bucket location for %s, %v", aws.ToString(bucket.Name), err)
+		}
+		region := string(location.LocationConstraint)
+		// Buckets in us-east-1 report an empty location constraint.
+		if only && !selected[region] && !(region == "" && selected[defaultRegion]) {
+			continue
 		}
 
 		// Check if the bucket has an Object Lock configuration (immutability)
+		bucketRegion := region
+		if bucketRegion == "" {
+			bucketRegion = defaultRegion
+		}
 		objectLockConfig, err := svc.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
 			Bucket: bucket.Name,
-		})
+		}, func(o *s3.Options) { o.Region = bucketRegion })
 		immutable := false
 		if err == nil && objectLockConfig.ObjectLockConfiguration != nil {
 			immutable = objectLockConfig.ObjectLockConfiguration.ObjectLockEnabled == "Enabled"
 		}
 
 		buckets = append(buckets, S3BucketInfo{
-			Name:      aws.ToString(bucket.Name),
-			Region:    string(region.LocationConstraint),
-			Immutable: immutable,
+			Name:              aws.ToString(bucket.Name),
+			Region:            region,
+			Immutable:         immutable,
+			CreationTimestamp: timestamp(bucket.CreationDate),
 		})
 	}
 
 	return buckets, nil
 }
 
-func FetchRDSInstances(ctx context.Context) ([]RDSInstanceInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
-	}
-
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to describe regions, %v", err)
-	}
-
-	var allInstances []RDSInstanceInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
+func fetchRDSInstances(ctx context.Context, client *rds.Client, region string) ([]RDSInstanceInfo, error) {
+	var instances []RDSInstanceInfo
+	paginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
+			return instances, fmt.Errorf("unable to describe DB instances in region %s, %v", region, err)
 		}
-
-		regionRdsClient := rds.NewFromConfig(regionCfg)
-		result, err := regionRdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{})
-		if err != nil {
-			return nil, fmt.Errorf("unable to describe DB instances in region %s, %v", *region.RegionName, err)
-		}
-
-		for _, instance := range result.DBInstances {
-			allInstances = append(allInstances, RDSInstanceInfo{
-				InstanceID: aws.ToString(instance.DBInstanceIdentifier),
-				Engine:     aws.ToString(instance.Engine),
-				Status:     aws.ToString(instance.DBInstanceStatus),
-				Region:     *region.RegionName,
+		for _, instance := range page.DBInstances {
+			instances = append(instances, RDSInstanceInfo{
+				InstanceID:        aws.ToString(instance.DBInstanceIdentifier),
+				Engine:            aws.ToString(instance.Engine),
+				Status:            aws.ToString(instance.DBInstanceStatus),
+				Region:            region,
+				CreationTimestamp: timestamp(instance.InstanceCreateTime),
 			})
 		}
 	}
-
-	return allInstances, nil
+	return instances, nil
 }
 
-func FetchDynamoDBTables(ctx context.Context) ([]DynamoDBTableInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
-	}
-
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to describe regions, %v", err)
-	}
-
-	var allTables []DynamoDBTableInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
-		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
-		}
-
-		svc := dynamodb.NewFromConfig(regionCfg)
-		result, err := svc.ListTables(ctx, &dynamodb.ListTablesInput{})
+func fetchDynamoDBTables(ctx context.Context, client *dynamodb.Client, region string) ([]DynamoDBTableInfo, error) {
+	var tables []DynamoDBTableInfo
+	paginator := dynamodb.NewListTablesPaginator(client, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to list tables in region %s, %v", *region.RegionName, err)
+			return tables, fmt.Errorf("unable to list tables in region %s, %v", region, err)
 		}
-
-		for _, tableName := range result.TableNames {
-			describeResult, err := svc.DescribeTable(ctx, &dynamodb.DescribeTableInput{
-				TableName: &tableName,
+		for _, tableName := range page.TableNames {
+			describeResult, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+				TableName: aws.String(tableName),
 			})
 			if err != nil {
-				return nil, fmt.Errorf("unable to describe table %s in region %s, %v", tableName, *region.RegionName, err)
+				return tables, fmt.Errorf("unable to describe table %s in region %s, %v", tableName, region, err)
 			}
-			allTables = append(allTables, DynamoDBTableInfo{
-				TableName: tableName,
-				Status:    string(describeResult.Table.TableStatus),
-				Region:    *region.RegionName,
+			tables = append(tables, DynamoDBTableInfo{
+				TableName:         tableName,
+				Status:            string(describeResult.Table.TableStatus),
+				Region:            region,
+				CreationTimestamp: timestamp(describeResult.Table.CreationDateTime),
 			})
 		}
 	}
-
-	return allTables, nil
+	return tables, nil
 }
 
-func FetchVPCs(ctx context.Context) ([]VPCInfo, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config, %v", err)
-	}
-
-	ec2Client := ec2.NewFromConfig(cfg)
-	regionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to describe regions, %v", err)
-	}
-
-	var allVPCs []VPCInfo
-	for _, region := range regionsOutput.Regions {
-		regionCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region.RegionName))
+func fetchVPCs(ctx context.Context, client *ec2.Client, region string) ([]VPCInfo, error) {
+	var vpcs []VPCInfo
+	paginator := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config for region %s, %v", *region.RegionName, err)
+			return vpcs, fmt.Errorf("unable to describe VPCs in region %s, %v", region, err)
 		}
-
-		regionEc2Client := ec2.NewFromConfig(regionCfg)
-		result, err := regionEc2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
-		if err != nil {
-			return nil, fmt.Errorf("unable to describe VPCs in region %s, %v", *region.RegionName, err)
-		}
-
-		for _, vpc := range result.Vpcs {
-			allVPCs = append(allVPCs, VPCInfo{
+		for _, vpc := range page.Vpcs {
+			vpcs = append(vpcs, VPCInfo{
 				VPCID:  aws.ToString(vpc.VpcId),
 				State:  string(vpc.State),
-				Region: *region.RegionName,
+				Region: region,
 			})
 		}
 	}
-
-	return allVPCs, nil
+	return vpcs, nil
 }
 
-func CollectAWSData(ctx context.Context) (AWSData, error) {
-	var data AWSData
-	var err error
-
-	data.EC2Instances, err = FetchEC2Instances(ctx)
-	if err != nil {
-		return AWSData{}, err
-	}
-
-	data.S3Buckets, err = FetchS3Buckets(ctx)
-	if err != nil {
-		return AWSData{}, err
-	}
-
-	data.RDSInstances, err = FetchRDSInstances(ctx)
-	if err != nil {
-		return AWSData{}, err
-	}
-
-	data.DynamoDBTables, err = FetchDynamoDBTables(ctx)
-	if err != nil {
-		return AWSData{}, err
-	}
+// sortData orders every list by region and identifier, since tasks finish
+// in any order.
+func sortData(data *AWSData) {
+	sort.Slice(data.EC2Instances, func(i, j int) bool {
+		a, b := data.EC2Instances[i], data.EC2Instances[j]
+		return a.Region < b.Region || a.Region == b.Region && a.InstanceID < b.InstanceID
+	})
+	sort.Slice(data.EBSVolumes, func(i, j int) bool {
+		a, b := data.EBSVolumes[i], data.EBSVolumes[j]
+		return a.Region < b.Region || a.Region == b.Region && a.VolumeID < b.VolumeID
+	})
+	sort.Slice(data.EBSSnapshots, func(i, j int) bool {
+		a, b := data.EBSSnapshots[i], data.EBSSnapshots[j]
+		return a.Region < b.Region || a.Region == b.Region && a.SnapshotID < b.SnapshotID
+	})
+	sort.Slice(data.S3Buckets, func(i, j int) bool { return data.S3Buckets[i].Name < data.S3Buckets[j].Name })
+	sort.Slice(data.RDSInstances, func(i, j int) bool {
+		a, b := data.RDSInstances[i], data.RDSInstances[j]
+		return a.Region < b.Region || a.Region == b.Region && a.InstanceID < b.InstanceID
+	})
+	sort.Slice(data.DynamoDBTables, func(i, j int) bool {
+		a, b := data.DynamoDBTables[i], data.DynamoDBTables[j]
+		return a.Region < b.Region || a.Region == b.Region && a.TableName < b.TableName
+	})
+	sort.Slice(data.VPCs, func(i, j int) bool {
+		a, b := data.VPCs[i], data.VPCs[j]
+		return a.Region < b.Region || a.Region == b.Region && a.VPCID < b.VPCID
+	})
+}
 
-	data.VPCs, err = FetchVPCs(ctx)
-	if err != nil {
-		return AWSData{}, err
+// timestamp formats an AWS time as RFC3339 in UTC.
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
 	}
-
-	return data, nil
+	return t.UTC().Format(time.RFC3339)
 }
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// stubConfig returns a config that sends every request to url.
+func stubConfig(url string) aws.Config {
+	return aws.Config{
+		Region:       "eu-west-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		BaseEndpoint: aws.String(url),
+	}
+}
+
+func TestFetchEC2InstancesFollowsNextToken(t *testing.T) {
+	pages := map[string]string{
+		"":      `<item><instanceId>i-1</instanceId><instanceType>t3.micro</instanceType><instanceState><name>running</name></instanceState></item>`,
+		"page2": `<item><instanceId>i-2</instanceId><instanceType>t3.large</instanceType><instanceState><name>stopped</name></instanceState></item>`,
+	}
+	next := map[string]string{"": "page2"}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		token := r.PostForm.Get("NextToken")
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>req</requestId>`+
+			`<reservationSet><item><instancesSet>%s</instancesSet></item></reservationSet>`, pages[token])
+		if next[token] != "" {
+			fmt.Fprintf(w, `<nextToken>%s</nextToken>`, next[token])
+		}
+		fmt.Fprint(w, `</DescribeInstancesResponse>`)
+	}))
+	defer srv.Close()
+
+	instances, err := fetchEC2Instances(context.Background(), ec2.NewFromConfig(stubConfig(srv.URL)), "eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d requests, want 2", calls)
+	}
+	if len(instances) != 2 || instances[0].InstanceID != "i-1" || instances[1].InstanceID != "i-2" {
+		t.Fatalf("got instances %+v, want i-1 and i-2", instances)
+	}
+	if instances[1].State != "stopped" || instances[1].Region != "eu-west-1" {
+		t.Errorf("got %+v, want a stopped instance in eu-west-1", instances[1])
+	}
+}
+
+func TestFetchDynamoDBTablesFollowsLastEvaluatedTableName(t *testing.T) {
+	pages := map[string][]string{"": {"orders"}, "orders": {"users"}}
+	next := map[string]string{"": "orders"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.ListTables":
+			start, _ := input["ExclusiveStartTableName"].(string)
+			output := map[string]interface{}{"TableNames": pages[start]}
+			if next[start] != "" {
+				output["LastEvaluatedTableName"] = next[start]
+			}
+			json.NewEncoder(w).Encode(output)
+		case "DynamoDB_20120810.DescribeTable":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"Table": map[string]interface{}{"TableName": input["TableName"], "TableStatus": "ACTIVE"},
+			})
+		default:
+			http.Error(w, "unexpected operation", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	tables, err := fetchDynamoDBTables(context.Background(), dynamodb.NewFromConfig(stubConfig(srv.URL)), "eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 || tables[0].TableName != "orders" || tables[1].TableName != "users" {
+		t.Fatalf("got tables %+v, want orders and users", tables)
+	}
+	if tables[1].Status != "ACTIVE" {
+		t.Errorf("got status %q, want ACTIVE", tables[1].Status)
+	}
+}
+
+func TestLoadConfigUsesConfiguredKeys(t *testing.T) {
+	cfg, err := loadConfig(context.Background(), Options{AccessKeyID: "AKID", SecretAccessKey: "SECRET", SessionToken: "TOKEN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "AKID" || creds.SecretAccessKey != "SECRET" || creds.SessionToken != "TOKEN" {
+		t.Errorf("got credentials %+v, want the configured key pair", creds)
+	}
+
+	if _, err := loadConfig(context.Background(), Options{AccessKeyID: "AKID"}); err == nil {
+		t.Error("loadConfig accepted an access key without a secret")
+	}
+}
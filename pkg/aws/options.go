@@ -0,0 +1,55 @@
+package aws
+
+import "time"
+
+const (
+	// DefaultWorkers is the number of region and service pairs fetched
+	// concurrently.
+	DefaultWorkers = 8
+	// DefaultSnapshotMaxAge is how old a volume's newest snapshot may be
+	// before the volume is flagged.
+	DefaultSnapshotMaxAge = 7 * 24 * time.Hour
+)
+
+// AccountPlaceholder is replaced with each account ID in
+// Options.AssumeRole.
+const AccountPlaceholder = "{account}"
+
+// Options tunes which accounts and regions are scanned and how.
+type Options struct {
+	// AccessKeyID, SecretAccessKey and SessionToken, if set, are used
+	// instead of the default credential chain, unless a key pair has been
+	// configured for the session with ConfigureCredentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Regions restricts collection to these regions. Empty means every
+	// region enabled for the account.
+	Regions []string
+	// Workers is the number of region and service pairs fetched
+	// concurrently.
+	Workers int
+	// Profiles are shared config profiles, each collected as its own
+	// account.
+	Profiles []string
+	// AssumeRole is a role ARN template, such as
+	// "arn:aws:iam::{account}:role/Inventory", assumed in each of
+	// AccountIDs with the default credentials.
+	AssumeRole string
+	// AccountIDs are the accounts AssumeRole is applied to.
+	AccountIDs []string
+	// SnapshotMaxAge is how old the newest completed snapshot of an EBS
+	// volume may be before the volume is flagged.
+	SnapshotMaxAge time.Duration
+}
+
+// DefaultOptions returns the options used when none are configured.
+func DefaultOptions() Options {
+	return Options{Workers: DefaultWorkers, SnapshotMaxAge: DefaultSnapshotMaxAge}
+}
+
+// multiAccount reports whether collection is split by account into
+// AWSData.Accounts.
+func (o Options) multiAccount() bool {
+	return len(o.Profiles) > 0 || len(o.AccountIDs) > 0
+}
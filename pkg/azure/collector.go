@@ -0,0 +1,48 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+func init() {
+	collector.Register("azure", NewCollector)
+}
+
+var collectorSchema = []collector.Field{
+	{Name: "azure-tenant-id", Description: "Azure tenant ID", Env: "AZURE_TENANT_ID", Required: true},
+	{Name: "azure-client-id", Description: "Azure client ID", Env: "AZURE_CLIENT_ID", Required: true},
+	{Name: "azure-client-secret", Description: "Azure client secret", Env: "AZURE_CLIENT_SECRET", Required: true, Secret: true},
+}
+
+// Collector collects Azure inventory through the collector registry.
+type Collector struct {
+	cfg collector.Config
+}
+
+// NewCollector returns an Azure collector configured from cfg.
+func NewCollector(cfg collector.Config) collector.Collector {
+	return &Collector{cfg: cfg.Resolve(collectorSchema)}
+}
+
+func (c *Collector) Name() string {
+	return "azure"
+}
+
+func (c *Collector) Schema() []collector.Field {
+	return collectorSchema
+}
+
+func (c *Collector) InventoryTypes() []collector.Inventory {
+	return []collector.Inventory{AzureData{}}
+}
+
+func (c *Collector) Collect(ctx context.Context) (collector.Inventory, error) {
+	return CollectAzureData(ctx)
+}
+
+func (c *Collector) Check(ctx context.Context) error {
+	_, err := CheckCredentials(ctx, c.cfg["azure-tenant-id"], c.cfg["azure-client-id"], c.cfg["azure-client-secret"])
+	return err
+}
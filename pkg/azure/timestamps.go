@@ -0,0 +1,106 @@
+package azure
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cosmos/armcosmos"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+// MarshalJSON writes every resource as Azure returns it, plus a
+// creationTimestamp in RFC3339 taken from wherever its type records one.
+// Blob containers and virtual networks have no creation time and are
+// written unchanged.
+func (d AzureData) MarshalJSON() ([]byte, error) {
+	var out struct {
+		AzureVMs             []map[string]interface{}
+		AzureVMSS            []map[string]interface{}
+		AzureAKSClusters     []map[string]interface{}
+		AzureStorageAccounts []map[string]interface{}
+		AzureBlobContainers  []armstorage.ListContainerItem
+		AzureVirtualNetworks []armnetwork.VirtualNetwork
+		AzureSQLDatabases    []map[string]interface{}
+		AzureCosmosDBs       []map[string]interface{}
+	}
+	var err error
+	if out.AzureVMs, err = stamp(d.AzureVMs, func(vm armcompute.VirtualMachine) *time.Time {
+		if vm.Properties == nil {
+			return nil
+		}
+		return vm.Properties.TimeCreated
+	}); err != nil {
+		return nil, err
+	}
+	if out.AzureVMSS, err = stamp(d.AzureVMSS, func(vmss armcompute.VirtualMachineScaleSet) *time.Time {
+		if vmss.Properties == nil {
+			return nil
+		}
+		return vmss.Properties.TimeCreated
+	}); err != nil {
+		return nil, err
+	}
+	if out.AzureAKSClusters, err = stamp(d.AzureAKSClusters, func(aks armcontainerservice.ManagedCluster) *time.Time {
+		if aks.SystemData == nil {
+			return nil
+		}
+		return aks.SystemData.CreatedAt
+	}); err != nil {
+		return nil, err
+	}
+	if out.AzureStorageAccounts, err = stamp(d.AzureStorageAccounts, func(account armstorage.Account) *time.Time {
+		if account.Properties == nil {
+			return nil
+		}
+		return account.Properties.CreationTime
+	}); err != nil {
+		return nil, err
+	}
+	if out.AzureSQLDatabases, err = stamp(d.AzureSQLDatabases, func(db armsql.Database) *time.Time {
+		if db.Properties == nil {
+			return nil
+		}
+		return db.Properties.CreationDate
+	}); err != nil {
+		return nil, err
+	}
+	if out.AzureCosmosDBs, err = stamp(d.AzureCosmosDBs, func(db armcosmos.DatabaseAccountGetResults) *time.Time {
+		if db.SystemData == nil {
+			return nil
+		}
+		return db.SystemData.CreatedAt
+	}); err != nil {
+		return nil, err
+	}
+	out.AzureBlobContainers = d.AzureBlobContainers
+	out.AzureVirtualNetworks = d.AzureVirtualNetworks
+	return json.Marshal(out)
+}
+
+// stamp converts items to their JSON objects and adds creationTimestamp to
+// those whose creation time is known.
+func stamp[T any](items []T, created func(T) *time.Time) ([]map[string]interface{}, error) {
+	if items == nil {
+		return nil, nil
+	}
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal(b, &object); err != nil {
+			return nil, err
+		}
+		if t := created(item); t != nil {
+			object["creationTimestamp"] = t.UTC().Format(time.RFC3339)
+		}
+		out = append(out, object)
+	}
+	return out, nil
+}
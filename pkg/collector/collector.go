@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Inventory is the document returned by a collector. Each platform returns
+// its own concrete type (k8sdata.K8sData, aws.AWSData, ...).
+type Inventory interface{}
+
+// Field describes a single configuration value a collector understands.
+// Name doubles as the CLI flag name and the key in Config.
+type Field struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Env         string `json:"env,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+}
+
+// Collector is implemented by every inventory backend.
+type Collector interface {
+	// Name is the key the collector is registered under, e.g. "aws".
+	Name() string
+	// Schema lists the configuration values the collector reads.
+	Schema() []Field
+	// Collect gathers the inventory for the platform.
+	Collect(ctx context.Context) (Inventory, error)
+	// Check verifies that the platform is reachable with the current
+	// configuration without doing a full collection.
+	Check(ctx context.Context) error
+}
+
+// Factory builds a collector from configuration.
+type Factory func(cfg Config) Collector
+
+// Config holds configuration values keyed by Field.Name.
+type Config map[string]string
+
+// Resolve returns a copy of c where every field that is unset has been
+// filled from its environment variable or default.
+func (c Config) Resolve(fields []Field) Config {
+	resolved := Config{}
+	for k, v := range c {
+		resolved[k] = v
+	}
+	for _, f := range fields {
+		if resolved[f.Name] != "" {
+			continue
+		}
+		if f.Env != "" {
+			if v := os.Getenv(f.Env); v != "" {
+				resolved[f.Name] = v
+				continue
+			}
+		}
+		if f.Default != "" {
+			resolved[f.Name] = f.Default
+		}
+	}
+	return resolved
+}
+
+// Validate returns an error naming every required field that is unset.
+func (c Config) Validate(fields []Field) error {
+	var missing []string
+	for _, f := range fields {
+		if f.Required && c[f.Name] == "" {
+			missing = append(missing, f.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Bool reports whether key is set to a true value.
+func (c Config) Bool(key string) bool {
+	switch strings.ToLower(c[key]) {
+	case "1", "t", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// List splits a comma separated value into its non-empty elements.
+func (c Config) List(key string) []string {
+	var values []string
+	for _, v := range strings.Split(c[key], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Factory{}
+)
+
+// Register makes a collector available under name. It is intended to be
+// called from the init function of the package implementing the collector
+// and panics if name is registered twice.
+func Register(name string, factory Factory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if factory == nil {
+		panic("collector: Register factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("collector: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// Names returns the sorted names of all registered collectors.
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds the collector registered under name.
+func New(name string, cfg Config) (Collector, error) {
+	registryMutex.RLock()
+	factory, ok := registry[name]
+	registryMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported inventory type: %s", name)
+	}
+	return factory(cfg), nil
+}
@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source records how collection went for a single platform.
+type Source struct {
+	StartedAt       time.Time `json:"startedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Document is the merged output of a collection run. Each inventory is
+// written under its collector name, next to the schema metadata and a
+// "sources" section with the timing and error of every collector that ran.
+type Document struct {
+	CollectedAt time.Time
+	ToolVersion string
+	Inventories map[string]Inventory
+	Sources     map[string]Source
+}
+
+// documentHeader holds the top-level keys of a serialised Document that are
+// not inventories.
+type documentHeader struct {
+	APIVersion  string            `json:"apiVersion"`
+	Kind        string            `json:"kind"`
+	CollectedAt time.Time         `json:"collectedAt"`
+	ToolVersion string            `json:"toolVersion,omitempty"`
+	Sources     map[string]Source `json:"sources,omitempty"`
+}
+
+var headerKeys = map[string]bool{"apiVersion": true, "kind": true, "collectedAt": true, "toolVersion": true, "sources": true}
+
+func (d Document) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(d.Inventories)+len(headerKeys))
+	for name, inventory := range d.Inventories {
+		out[name] = inventory
+	}
+	out["apiVersion"] = APIVersion
+	out["kind"] = Kind
+	out["collectedAt"] = d.CollectedAt
+	if d.ToolVersion != "" {
+		out["toolVersion"] = d.ToolVersion
+	}
+	if len(d.Sources) > 0 {
+		out["sources"] = d.Sources
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON keeps every inventory as raw JSON; use Decode to turn one
+// into its concrete type. Documents written before apiVersion was
+// introduced are accepted; any other apiVersion is rejected.
+func (d *Document) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var header documentHeader
+	if err := json.Unmarshal(b, &header); err != nil {
+		return fmt.Errorf("invalid document header: %v", err)
+	}
+	if header.APIVersion != "" && header.APIVersion != APIVersion {
+		return fmt.Errorf("unsupported apiVersion %q, expected %q", header.APIVersion, APIVersion)
+	}
+	d.CollectedAt = header.CollectedAt
+	d.ToolVersion = header.ToolVersion
+	d.Sources = header.Sources
+	d.Inventories = make(map[string]Inventory, len(raw))
+	for name, value := range raw {
+		if !headerKeys[name] {
+			d.Inventories[name] = value
+		}
+	}
+	return nil
+}
+
+// Decode stores the inventory collected under name in v, which must be a
+// pointer to the collector's concrete inventory type. It reports false if
+// the document has no inventory for name.
+func (d Document) Decode(name string, v interface{}) (bool, error) {
+	inventory, ok := d.Inventories[name]
+	if !ok || inventory == nil {
+		return false, nil
+	}
+	raw, ok := inventory.(json.RawMessage)
+	if !ok {
+		var err error
+		raw, err = json.Marshal(inventory)
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+// ParseNames splits a comma separated list of collector names. "all"
+// selects every registered collector.
+func ParseNames(list string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			return Names(), nil
+		}
+		registryMutex.RLock()
+		_, ok := registry[name]
+		registryMutex.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unsupported inventory type: %s", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no inventory type given")
+	}
+	return names, nil
+}
+
+// Run collects from every collector concurrently and merges the results.
+// A failing collector does not stop the others; its error is recorded in
+// the document's sources.
+func Run(ctx context.Context, collectors []Collector) Document {
+	doc := Document{
+		CollectedAt: time.Now().UTC().Truncate(time.Second),
+		ToolVersion: Version,
+		Inventories: make(map[string]Inventory, len(collectors)),
+		Sources:     make(map[string]Source, len(collectors)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range collectors {
+		wg.Add(1)
+		go func(c Collector) {
+			defer wg.Done()
+			start := time.Now()
+			inventory, err := c.Collect(ctx)
+			source := Source{
+				StartedAt:       start.UTC(),
+				DurationSeconds: time.Since(start).Seconds(),
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				source.Error = err.Error()
+			} else {
+				doc.Inventories[c.Name()] = inventory
+			}
+			doc.Sources[c.Name()] = source
+		}(c)
+	}
+	wg.Wait()
+	return doc
+}
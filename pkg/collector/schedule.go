@@ -0,0 +1,255 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Statuses reported in SourceStatus.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusOK      = "ok"
+	StatusError   = "error"
+)
+
+// SourceStatus reports the schedule and latest run of one collector.
+type SourceStatus struct {
+	Status          string     `json:"status"`
+	IntervalSeconds float64    `json:"intervalSeconds"`
+	LastRun         *time.Time `json:"lastRun,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	Error           string     `json:"error,omitempty"`
+	LastSuccess     *time.Time `json:"lastSuccess,omitempty"`
+	NextRun         *time.Time `json:"nextRun,omitempty"`
+	Runs            int        `json:"runs"`
+	Failures        int        `json:"failures"`
+}
+
+// Scheduler re-runs collectors on their own intervals and keeps a Document
+// merged from their latest results. A collector that fails keeps its last
+// successful inventory; the failure is recorded in the document's sources.
+type Scheduler struct {
+	Collectors []Collector
+	// Interval is the time between runs of a collector unless Intervals
+	// sets one for it by name.
+	Interval  time.Duration
+	Intervals map[string]time.Duration
+	// Jitter spreads runs by up to this fraction of the interval either
+	// way, so that collectors sharing an interval do not run in lockstep.
+	Jitter float64
+	// OnUpdate, if set, is called with the new document after every run.
+	OnUpdate func(Document)
+
+	mu       sync.Mutex
+	doc      Document
+	status   map[string]*SourceStatus
+	triggers map[string]chan chan struct{}
+	updateMu sync.Mutex
+}
+
+// Run collects from every collector straight away and then on its
+// schedule until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.doc = Document{
+		ToolVersion: Version,
+		Inventories: map[string]Inventory{},
+		Sources:     map[string]Source{},
+	}
+	s.status = make(map[string]*SourceStatus, len(s.Collectors))
+	s.triggers = make(map[string]chan chan struct{}, len(s.Collectors))
+	for _, c := range s.Collectors {
+		s.status[c.Name()] = &SourceStatus{
+			Status:          StatusPending,
+			IntervalSeconds: s.interval(c.Name()).Seconds(),
+		}
+		s.triggers[c.Name()] = make(chan chan struct{})
+	}
+	triggers := s.triggers
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range s.Collectors {
+		wg.Add(1)
+		go func(c Collector) {
+			defer wg.Done()
+			s.loop(ctx, c, triggers[c.Name()])
+		}(c)
+	}
+	wg.Wait()
+}
+
+// Collect runs the named collectors now, in their scheduled loops so that
+// their next runs are counted from this one, and waits until they have
+// finished and the new document has been handed to OnUpdate.
+func (s *Scheduler) Collect(ctx context.Context, names []string) error {
+	s.mu.Lock()
+	triggers := s.triggers
+	s.mu.Unlock()
+	if triggers == nil {
+		return fmt.Errorf("the scheduler is not running")
+	}
+	for _, name := range names {
+		if _, ok := triggers[name]; !ok {
+			return fmt.Errorf("%s is not collected on a schedule", name)
+		}
+	}
+	var pending []chan struct{}
+	for _, name := range names {
+		done := make(chan struct{})
+		select {
+		case triggers[name] <- done:
+			pending = append(pending, done)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for _, done := range pending {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Document returns the latest merged document.
+func (s *Scheduler) Document() Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc
+}
+
+// Status returns a copy of every collector's status keyed by name.
+func (s *Scheduler) Status() map[string]SourceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := make(map[string]SourceStatus, len(s.status))
+	for name, st := range s.status {
+		status[name] = *st
+	}
+	return status
+}
+
+// loop runs c on its schedule, and straight away whenever Collect sends it
+// a channel on trigger, which it closes once that run is done.
+func (s *Scheduler) loop(ctx context.Context, c Collector, trigger chan chan struct{}) {
+	interval := s.interval(c.Name())
+	var done chan struct{}
+	for {
+		s.collect(ctx, c)
+		if done != nil {
+			close(done)
+			done = nil
+		}
+		next := time.Now().Add(s.jitter(interval))
+		s.mu.Lock()
+		s.status[c.Name()].NextRun = &next
+		s.mu.Unlock()
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case done = <-trigger:
+			timer.Stop()
+		}
+	}
+}
+
+// collect runs c once and swaps in a new document holding its result. The
+// previous document is never modified, so readers holding it are safe.
+func (s *Scheduler) collect(ctx context.Context, c Collector) {
+	name := c.Name()
+	s.mu.Lock()
+	s.status[name].Status = StatusRunning
+	s.mu.Unlock()
+
+	result := Run(ctx, []Collector{c})
+	source := result.Sources[name]
+
+	s.mu.Lock()
+	doc := Document{
+		CollectedAt: result.CollectedAt,
+		ToolVersion: s.doc.ToolVersion,
+		Inventories: make(map[string]Inventory, len(s.doc.Inventories)+1),
+		Sources:     make(map[string]Source, len(s.doc.Sources)+1),
+	}
+	for k, v := range s.doc.Inventories {
+		doc.Inventories[k] = v
+	}
+	for k, v := range s.doc.Sources {
+		doc.Sources[k] = v
+	}
+	doc.Sources[name] = source
+
+	st := s.status[name]
+	startedAt := source.StartedAt
+	st.LastRun = &startedAt
+	st.DurationSeconds = source.DurationSeconds
+	st.Error = source.Error
+	st.Runs++
+	if source.Error != "" {
+		st.Status = StatusError
+		st.Failures++
+	} else {
+		st.Status = StatusOK
+		st.LastSuccess = &startedAt
+		doc.Inventories[name] = result.Inventories[name]
+	}
+	s.doc = doc
+	s.mu.Unlock()
+
+	if s.OnUpdate != nil {
+		// Collectors finishing together must not hand an older document
+		// over after a newer one.
+		s.updateMu.Lock()
+		s.OnUpdate(s.Document())
+		s.updateMu.Unlock()
+	}
+}
+
+func (s *Scheduler) interval(name string) time.Duration {
+	if d, ok := s.Intervals[name]; ok && d > 0 {
+		return d
+	}
+	return s.Interval
+}
+
+func (s *Scheduler) jitter(interval time.Duration) time.Duration {
+	if s.Jitter <= 0 {
+		return interval
+	}
+	spread := float64(interval) * s.Jitter
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+// DocumentStatus describes the sources of a document collected once, as
+// a Scheduler would after a single run of each collector.
+func DocumentStatus(doc Document) map[string]SourceStatus {
+	status := make(map[string]SourceStatus, len(doc.Sources))
+	for name, source := range doc.Sources {
+		startedAt := source.StartedAt
+		st := SourceStatus{
+			Status:          StatusOK,
+			LastRun:         &startedAt,
+			DurationSeconds: source.DurationSeconds,
+			Error:           source.Error,
+			Runs:            1,
+		}
+		if source.Error != "" {
+			st.Status = StatusError
+			st.Failures = 1
+		} else {
+			st.LastSuccess = &startedAt
+		}
+		status[name] = st
+	}
+	return status
+}
@@ -0,0 +1,171 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCollector returns the number of its run as the inventory, or fails
+// the runs fail reports true for.
+type fakeCollector struct {
+	name string
+	fail func(run int) bool
+
+	mu   sync.Mutex
+	runs int
+}
+
+func (f *fakeCollector) Name() string    { return f.name }
+func (f *fakeCollector) Schema() []Field { return nil }
+
+func (f *fakeCollector) Collect(ctx context.Context) (Inventory, error) {
+	f.mu.Lock()
+	f.runs++
+	run := f.runs
+	f.mu.Unlock()
+	if f.fail != nil && f.fail(run) {
+		return nil, errors.New("unreachable")
+	}
+	return run, nil
+}
+
+func (f *fakeCollector) Check(ctx context.Context) error { return nil }
+
+// waitFor polls cond until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerInterval(t *testing.T) {
+	s := &Scheduler{
+		Interval:  15 * time.Minute,
+		Intervals: map[string]time.Duration{"aws": time.Hour, "veeam": 0},
+	}
+	for name, want := range map[string]time.Duration{"aws": time.Hour, "veeam": 15 * time.Minute, "kubernetes": 15 * time.Minute} {
+		if got := s.interval(name); got != want {
+			t.Errorf("interval(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	s := &Scheduler{}
+	if got := s.jitter(time.Hour); got != time.Hour {
+		t.Errorf("jitter without Jitter = %s", got)
+	}
+	s.Jitter = 0.1
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 1000; i++ {
+		d := s.jitter(time.Hour)
+		if d < 54*time.Minute || d > 66*time.Minute {
+			t.Fatalf("jitter = %s, outside 10%% of an hour", d)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Error("jitter never varies")
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	fast := &fakeCollector{name: "fast", fail: func(run int) bool { return run > 1 }}
+	slow := &fakeCollector{name: "slow"}
+	var mu sync.Mutex
+	var updates []Document
+	s := &Scheduler{
+		Collectors: []Collector{fast, slow},
+		Interval:   time.Hour,
+		Intervals:  map[string]time.Duration{"fast": 10 * time.Millisecond},
+		OnUpdate: func(doc Document) {
+			mu.Lock()
+			updates = append(updates, doc)
+			mu.Unlock()
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, "three runs of fast", func() bool { return s.Status()["fast"].Runs >= 3 })
+	waitFor(t, "a run of slow", func() bool { return s.Status()["slow"].Runs == 1 })
+	cancel()
+	<-done
+
+	status := s.Status()
+	if st := status["fast"]; st.Status != StatusError || st.Failures != st.Runs-1 || st.LastSuccess == nil || st.Error == "" {
+		t.Errorf("fast status = %+v", st)
+	}
+	if st := status["slow"]; st.Status != StatusOK || st.Runs != 1 || st.IntervalSeconds != 3600 || st.NextRun == nil || time.Until(*st.NextRun) < 59*time.Minute {
+		t.Errorf("slow status = %+v", st)
+	}
+	doc := s.Document()
+	// A failing collector keeps its last successful inventory.
+	if doc.Inventories["fast"] != 1 || doc.Inventories["slow"] != 1 {
+		t.Errorf("inventories = %v", doc.Inventories)
+	}
+	if doc.Sources["fast"].Error == "" || doc.Sources["slow"].Error != "" {
+		t.Errorf("sources = %+v", doc.Sources)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updates) != status["fast"].Runs+status["slow"].Runs {
+		t.Errorf("OnUpdate called %d times for %d runs", len(updates), status["fast"].Runs+status["slow"].Runs)
+	}
+}
+
+func TestSchedulerCollect(t *testing.T) {
+	c := &fakeCollector{name: "a"}
+	var mu sync.Mutex
+	var last Document
+	s := &Scheduler{
+		Collectors: []Collector{c},
+		Interval:   time.Hour,
+		OnUpdate: func(doc Document) {
+			mu.Lock()
+			last = doc
+			mu.Unlock()
+		},
+	}
+	if err := s.Collect(context.Background(), []string{"a"}); err == nil {
+		t.Error("Collect succeeded before Run")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	waitFor(t, "the first run", func() bool { return s.Status()["a"].Runs == 1 })
+	first := *s.Status()["a"].NextRun
+
+	if err := s.Collect(ctx, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	st := s.Status()["a"]
+	if st.Runs != 2 {
+		t.Errorf("runs = %d after Collect, want 2", st.Runs)
+	}
+	// Collect returns after OnUpdate has the new document.
+	mu.Lock()
+	if last.Inventories["a"] != 2 {
+		t.Errorf("OnUpdate last got inventory %v, want 2", last.Inventories["a"])
+	}
+	mu.Unlock()
+	waitFor(t, "the next run to be rescheduled", func() bool {
+		next := s.Status()["a"].NextRun
+		return next != nil && next.After(first)
+	})
+
+	if err := s.Collect(ctx, []string{"b"}); err == nil {
+		t.Error("Collect accepted a collector that is not scheduled")
+	}
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"runtime/debug"
+
+	"github.com/michaelcade/kollect/pkg/jsonschema"
+)
+
+// Identifiers written at the top of every Document. APIVersion changes
+// whenever the output changes incompatibly.
+const (
+	APIVersion = "kollect.io/v1"
+	Kind       = "Inventory"
+)
+
+// SchemaID is the $id of the published JSON Schema for Documents.
+const SchemaID = "https://github.com/michaelcade/kollect/api/v1/kollect.schema.json"
+
+// Version is the kollect release recorded in every Document. Release builds
+// set it with -ldflags "-X github.com/michaelcade/kollect/pkg/collector.Version=<tag>";
+// otherwise the module version from the build info is used.
+var Version = "dev"
+
+func init() {
+	if Version != "dev" {
+		return
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = info.Main.Version
+	}
+}
+
+// Describer is implemented by collectors that can describe the inventory
+// they return. The published JSON Schema is generated from these values.
+type Describer interface {
+	// InventoryTypes returns a zero value of every concrete type Collect
+	// may return.
+	InventoryTypes() []Inventory
+}
+
+// DocumentSchema generates the JSON Schema for a Document holding any of
+// the registered inventories.
+func DocumentSchema() *jsonschema.Schema {
+	r := &jsonschema.Reflector{Packages: []string{"github.com/michaelcade/kollect/"}}
+	s := &jsonschema.Schema{
+		Schema: jsonschema.Draft,
+		ID:     SchemaID,
+		Title:  "kollect inventory",
+		Type:   "object",
+		Properties: map[string]*jsonschema.Schema{
+			"apiVersion":  {Type: "string", Const: APIVersion},
+			"kind":        {Type: "string", Const: Kind},
+			"collectedAt": {Type: "string", Format: "date-time"},
+			"toolVersion": {Type: "string"},
+			"sources": {
+				Type:                 "object",
+				Description:          "Timing and error of every collector that ran, keyed by inventory name.",
+				AdditionalProperties: r.Reflect(Source{}),
+			},
+		},
+		Required: []string{"apiVersion", "kind", "collectedAt"},
+	}
+	for _, name := range Names() {
+		c, err := New(name, Config{})
+		if err != nil {
+			continue
+		}
+		describer, ok := c.(Describer)
+		if !ok {
+			s.Properties[name] = &jsonschema.Schema{}
+			continue
+		}
+		var variants []*jsonschema.Schema
+		for _, inventory := range describer.InventoryTypes() {
+			variants = append(variants, r.Reflect(inventory))
+		}
+		if len(variants) == 1 {
+			s.Properties[name] = variants[0]
+		} else {
+			s.Properties[name] = &jsonschema.Schema{OneOf: variants}
+		}
+	}
+	s.Defs = r.Defs
+	return s
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileName returns the CSV file name of t, e.g. "kubernetes_pods.csv".
+func (t Table) FileName() string {
+	return t.Platform + "_" + t.Type + ".csv"
+}
+
+// WriteCSV writes t with a header row of its columns.
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteCSVDir writes each table to its own file in dir, creating dir if
+// needed.
+func WriteCSVDir(dir string, tables []Table) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		f, err := os.Create(filepath.Join(dir, t.FileName()))
+		if err != nil {
+			return err
+		}
+		if err := WriteCSV(f, t); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSVZip writes a zip archive holding one CSV file per table.
+func WriteCSVZip(w io.Writer, tables []Table) error {
+	zw := zip.NewWriter(w)
+	for _, t := range tables {
+		f, err := zw.Create(t.FileName())
+		if err != nil {
+			return err
+		}
+		if err := WriteCSV(f, t); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var csvTables = []Table{
+	{Platform: "kubernetes", Type: "pods", Columns: []string{"namespace", "name"}, Rows: [][]string{{"default", "a,b"}, {"", "line\nbreak"}}},
+	{Platform: "aws", Type: "ec2Instances", Columns: []string{"id"}, Rows: [][]string{{`i-"1"`}}},
+}
+
+func readCSV(t *testing.T, b []byte) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func wantRecords(table Table) [][]string {
+	return append([][]string{table.Columns}, table.Rows...)
+}
+
+func TestWriteCSVZip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSVZip(&buf, csvTables); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != len(csvTables) {
+		t.Fatalf("archive has %d files, want %d", len(zr.File), len(csvTables))
+	}
+	for i, f := range zr.File {
+		if f.Name != csvTables[i].FileName() {
+			t.Errorf("file %d is %s, want %s", i, f.Name, csvTables[i].FileName())
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		b.ReadFrom(rc)
+		rc.Close()
+		if got := readCSV(t, b.Bytes()); !reflect.DeepEqual(got, wantRecords(csvTables[i])) {
+			t.Errorf("%s = %q", f.Name, got)
+		}
+	}
+}
+
+func TestWriteCSVDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	if err := WriteCSVDir(dir, csvTables); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range csvTables {
+		b, err := os.ReadFile(filepath.Join(dir, table.FileName()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := readCSV(t, b); !reflect.DeepEqual(got, wantRecords(table)) {
+			t.Errorf("%s = %q", table.FileName(), got)
+		}
+	}
+}
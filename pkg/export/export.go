@@ -0,0 +1,197 @@
+// Package export flattens collected inventories into tables, one per
+// resource type, and writes them as CSV files or an XLSX workbook.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// Table is one resource type, e.g. kubernetes pods, with a row per object.
+// Nested objects are flattened into dotted column names.
+type Table struct {
+	Platform string
+	Type     string
+	Columns  []string
+	Rows     [][]string
+}
+
+// Name returns the table's name, e.g. "kubernetes.pods".
+func (t Table) Name() string {
+	return t.Platform + "." + t.Type
+}
+
+// leadingColumns come first, in this order, in every table that has them;
+// the remaining columns follow alphabetically.
+var leadingColumns = []string{"cluster", "account", "id", "arn", "region", "location", "namespace", "name"}
+
+// scopeMaps hold one inventory per cluster or account in multi-cluster and
+// multi-account runs. Their lists are merged into one table with the
+// column each map names.
+var scopeMaps = map[string]string{"clusters": "cluster", "accounts": "account"}
+
+// Tables flattens every list in doc into a table, ordered by name.
+func Tables(doc collector.Document) ([]Table, error) {
+	builders := map[string]*builder{}
+	for platform := range doc.Inventories {
+		var value interface{}
+		if _, err := doc.Decode(platform, &value); err != nil {
+			return nil, fmt.Errorf("%s: %v", platform, err)
+		}
+		walk(builders, platform, "", scope{}, value)
+	}
+	tables := make([]Table, 0, len(builders))
+	for _, b := range builders {
+		tables = append(tables, b.table())
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name() < tables[j].Name() })
+	return tables, nil
+}
+
+// builder collects the rows of one table before its columns are known.
+type builder struct {
+	platform, typ string
+	rows          []map[string]string
+}
+
+// scope is the cluster or account rows are collected under, written to
+// the column named by kind.
+type scope struct {
+	name, kind string
+}
+
+func walk(builders map[string]*builder, platform, typ string, sc scope, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range sortedNames(v) {
+			child := v[name]
+			if kind, ok := scopeMaps[name]; ok {
+				if scopes, ok := child.(map[string]interface{}); ok {
+					for _, s := range sortedNames(scopes) {
+						walk(builders, platform, typ, scope{s, kind}, scopes[s])
+					}
+					continue
+				}
+			}
+			path := name
+			if typ != "" {
+				path = typ + "." + name
+			}
+			walk(builders, platform, path, sc, child)
+		}
+	case []interface{}:
+		if len(v) == 0 || typ == "" {
+			return
+		}
+		key := platform + "." + typ
+		b, ok := builders[key]
+		if !ok {
+			b = &builder{platform: platform, typ: typ}
+			builders[key] = b
+		}
+		for _, item := range v {
+			row := map[string]string{}
+			if sc.name != "" {
+				row[sc.kind] = sc.name
+			}
+			if object, ok := item.(map[string]interface{}); ok {
+				flatten(row, "", object)
+			} else {
+				row["value"] = cell(item)
+			}
+			b.rows = append(b.rows, row)
+		}
+	}
+}
+
+// flatten writes the fields of object into row, descending into nested
+// objects. Lists are kept in one cell.
+func flatten(row map[string]string, prefix string, object map[string]interface{}) {
+	for name, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flatten(row, prefix+name+".", nested)
+			continue
+		}
+		row[prefix+name] = cell(value)
+	}
+}
+
+// cell formats a decoded JSON value. Lists of scalars are joined with
+// "; "; anything else that is not a scalar is written as JSON.
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return jsonCell(v)
+			}
+			parts = append(parts, cell(item))
+		}
+		return strings.Join(parts, "; ")
+	}
+	return jsonCell(value)
+}
+
+func jsonCell(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+func (b *builder) table() Table {
+	seen := map[string]bool{}
+	for _, row := range b.rows {
+		for column := range row {
+			seen[column] = true
+		}
+	}
+	var columns []string
+	for _, column := range leadingColumns {
+		if seen[column] {
+			columns = append(columns, column)
+			delete(seen, column)
+		}
+	}
+	rest := make([]string, 0, len(seen))
+	for column := range seen {
+		rest = append(rest, column)
+	}
+	sort.Strings(rest)
+	columns = append(columns, rest...)
+
+	t := Table{Platform: b.platform, Type: b.typ, Columns: columns}
+	for _, row := range b.rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = row[column]
+		}
+		t.Rows = append(t.Rows, cells)
+	}
+	return t
+}
+
+func sortedNames(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteXLSXPartsAreXML(t *testing.T) {
+	tables := []Table{
+		{Platform: "kubernetes", Type: "pods", Columns: []string{"namespace", "name"}, Rows: [][]string{
+			{"default", `<a & "b">`},
+			{"", "control\x01char"},
+		}},
+		{Platform: "aws", Type: "pods", Columns: []string{"name"}, Rows: [][]string{{"é"}}},
+	}
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, tables); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]bool{}
+	for _, f := range zr.File {
+		parts[f.Name] = true
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := xml.NewDecoder(rc)
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("%s: %v", f.Name, err)
+				break
+			}
+		}
+		rc.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if !parts[name] {
+			t.Errorf("workbook has no %s", name)
+		}
+	}
+}
+
+func TestWriteXLSXEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			return
+		}
+	}
+	t.Error("empty workbook has no sheet")
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestSheetNames(t *testing.T) {
+	long := strings.Repeat("x", 40)
+	tests := []struct {
+		name   string
+		tables []Table
+		want   []string
+	}{
+		{
+			name:   "shared type is qualified",
+			tables: []Table{{Platform: "aws", Type: "pods"}, {Platform: "kubernetes", Type: "pods"}, {Platform: "kubernetes", Type: "nodes"}},
+			want:   []string{"aws.pods", "kubernetes.pods", "nodes"},
+		},
+		{
+			name:   "invalid characters",
+			tables: []Table{{Platform: "veeam", Type: `a[b]c:d*e?f/g\h`}},
+			want:   []string{"a_b_c_d_e_f_g_h"},
+		},
+		{
+			name:   "too long",
+			tables: []Table{{Platform: "aws", Type: long}},
+			want:   []string{long[:31]},
+		},
+		{
+			name:   "duplicates after truncation",
+			tables: []Table{{Platform: "aws", Type: long + "1"}, {Platform: "aws", Type: long + "2"}, {Platform: "aws", Type: long + "3"}},
+			want:   []string{long[:31], long[:29] + "~2", long[:29] + "~3"},
+		},
+		{
+			name:   "duplicates differing in case",
+			tables: []Table{{Platform: "a", Type: "Jobs"}, {Platform: "b", Type: "jobs"}},
+			want:   []string{"Jobs", "jobs~2"},
+		},
+		{
+			name:   "multi-byte characters",
+			tables: []Table{{Platform: "azure", Type: strings.Repeat("é", 40)}},
+			want:   []string{strings.Repeat("é", 31)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sheetNames(tt.tables)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("sheetNames = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRowTruncatesLongCells(t *testing.T) {
+	// Put a two-byte character across the limit.
+	value := strings.Repeat("a", maxCellLength-1) + "é" + "tail"
+	var b strings.Builder
+	writeRow(&b, 1, []string{value}, 0)
+
+	var row struct {
+		Cells []struct {
+			Text string `xml:"is>t"`
+		} `xml:"c"`
+	}
+	if err := xml.Unmarshal([]byte(b.String()), &row); err != nil {
+		t.Fatal(err)
+	}
+	if len(row.Cells) != 1 {
+		t.Fatalf("row has %d cells", len(row.Cells))
+	}
+	got := row.Cells[0].Text
+	if !utf8.ValidString(got) {
+		t.Error("truncated cell is not valid UTF-8")
+	}
+	if len(got) > maxCellLength {
+		t.Errorf("cell has %d bytes, more than %d", len(got), maxCellLength)
+	}
+	if got != strings.Repeat("a", maxCellLength-1) {
+		t.Errorf("cell was not cut before the multi-byte character")
+	}
+}
@@ -0,0 +1,283 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// Resource identifies an object in an inventory. Type is the JSON path of
+// the list holding it, e.g. "pods" or "velero.backups".
+type Resource struct {
+	Platform string `json:"platform"`
+	Type     string `json:"type"`
+	Key      string `json:"key"`
+}
+
+// Change is a resource present in both runs whose content differs.
+type Change struct {
+	Resource
+	// Fields lists the top-level fields whose values changed.
+	Fields []string `json:"fields"`
+}
+
+// Diff is the difference between two runs.
+type Diff struct {
+	Added   []Resource `json:"added,omitempty"`
+	Removed []Resource `json:"removed,omitempty"`
+	Changed []Change   `json:"changed,omitempty"`
+}
+
+// identityKeys are tried in order to find a stable key for an object. The
+// first set whose last field is present wins, and the key joins the fields
+// of that set that are present. ARNs, Azure resource IDs and Veeam IDs come
+// first, then AWS identifiers that are only unique within a region, then
+// Kubernetes namespace/name.
+var identityKeys = [][]string{
+	{"arn"},
+	{"id"},
+	{"region", "instanceID"},
+	{"region", "vpcID"},
+	{"region", "tableName"},
+	{"region", "snapshotID"},
+	{"region", "volumeID"},
+	{"group", "resource"},
+	{"namespace", "name"},
+	{"namespace"},
+	{"resource"},
+}
+
+// keyedMaps are objects whose keys name a scope, such as the clusters of a
+// multi-cluster run or the accounts of a multi-account AWS run, rather than
+// a resource type. The scope becomes part of each resource's key. Values
+// are the kind of scope each map holds.
+var keyedMaps = map[string]string{"clusters": "cluster", "accounts": "account"}
+
+// Compare reports the resources added, removed and changed between from and
+// to. Resources are the objects in the lists of each inventory that carry a
+// stable identity; objects without one, such as graph edges, are not
+// compared.
+func Compare(from, to collector.Document) (Diff, error) {
+	before, err := resources(from)
+	if err != nil {
+		return Diff{}, err
+	}
+	after, err := resources(to)
+	if err != nil {
+		return Diff{}, err
+	}
+	var diff Diff
+	for r, old := range before {
+		current, ok := after[r]
+		if !ok {
+			diff.Removed = append(diff.Removed, r)
+			continue
+		}
+		if fields := changedFields(old, current); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Change{Resource: r, Fields: fields})
+		}
+	}
+	for r := range after {
+		if _, ok := before[r]; !ok {
+			diff.Added = append(diff.Added, r)
+		}
+	}
+	sortResources(diff.Added)
+	sortResources(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return less(diff.Changed[i].Resource, diff.Changed[j].Resource) })
+	return diff, nil
+}
+
+// Empty reports whether the runs hold the same resources.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// WriteText writes the diff grouped by platform, one resource per line
+// prefixed with +, - or ~.
+func (d Diff) WriteText(w io.Writer) {
+	if d.Empty() {
+		fmt.Fprintln(w, "No differences")
+		return
+	}
+	type line struct {
+		Resource
+		text string
+	}
+	var lines []line
+	for _, r := range d.Added {
+		lines = append(lines, line{r, fmt.Sprintf("  + %s %s", r.Type, r.Key)})
+	}
+	for _, r := range d.Removed {
+		lines = append(lines, line{r, fmt.Sprintf("  - %s %s", r.Type, r.Key)})
+	}
+	for _, c := range d.Changed {
+		lines = append(lines, line{c.Resource, fmt.Sprintf("  ~ %s %s (%s)", c.Type, c.Key, strings.Join(c.Fields, ", "))})
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return less(lines[i].Resource, lines[j].Resource) })
+	platform := ""
+	for _, l := range lines {
+		if l.Platform != platform {
+			platform = l.Platform
+			fmt.Fprintln(w, platform)
+		}
+		fmt.Fprintln(w, l.text)
+	}
+}
+
+// Object is an identifiable object in an inventory. Scope is the part of
+// its key naming the cluster or account it came from in multi-cluster and
+// multi-account runs, and ScopeKind says which.
+type Object struct {
+	Resource
+	Scope     string
+	ScopeKind string
+	Fields    map[string]interface{}
+}
+
+// Objects flattens every inventory in doc into its identifiable objects.
+// Lists of names, such as namespaces, give objects without fields.
+func Objects(doc collector.Document) ([]Object, error) {
+	var out []Object
+	for platform := range doc.Inventories {
+		var value interface{}
+		if _, err := doc.Decode(platform, &value); err != nil {
+			return nil, fmt.Errorf("%s: %v", platform, err)
+		}
+		out = collect(out, platform, "", scope{}, value)
+	}
+	return out, nil
+}
+
+// resources indexes the objects of doc by identity.
+func resources(doc collector.Document) (map[Resource]map[string]interface{}, error) {
+	objects, err := Objects(doc)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[Resource]map[string]interface{}, len(objects))
+	for _, object := range objects {
+		out[object.Resource] = object.Fields
+	}
+	return out, nil
+}
+
+// scope is the cluster or account objects are collected under.
+type scope struct {
+	name, kind string
+}
+
+func collect(out []Object, platform, typ string, sc scope, value interface{}) []Object {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			if kind, ok := keyedMaps[name]; ok {
+				if scopes, ok := child.(map[string]interface{}); ok {
+					for s, scoped := range scopes {
+						out = collect(out, platform, typ, scope{join(sc.name, s), kind}, scoped)
+					}
+					continue
+				}
+			}
+			out = collect(out, platform, joinType(typ, name), sc, child)
+		}
+	case []interface{}:
+		// Items without an identity are skipped, not the whole list.
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				out = append(out, Object{Resource: Resource{Platform: platform, Type: typ, Key: join(sc.name, name)}, Scope: sc.name, ScopeKind: sc.kind})
+				continue
+			}
+			fields, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := identity(fields)
+			if key == "" {
+				continue
+			}
+			out = append(out, Object{Resource: Resource{Platform: platform, Type: typ, Key: join(sc.name, key)}, Scope: sc.name, ScopeKind: sc.kind, Fields: fields})
+		}
+	}
+	return out
+}
+
+// identity returns the stable key of an object, or "" if it has none.
+func identity(object map[string]interface{}) string {
+	for _, fields := range identityKeys {
+		if stringField(object, fields[len(fields)-1]) == "" {
+			continue
+		}
+		key := ""
+		for _, field := range fields {
+			if value := stringField(object, field); value != "" {
+				key = join(key, value)
+			}
+		}
+		return key
+	}
+	return ""
+}
+
+func stringField(object map[string]interface{}, field string) string {
+	s, _ := object[field].(string)
+	return s
+}
+
+// changedFields returns the sorted names of the fields that differ.
+func changedFields(old, current map[string]interface{}) []string {
+	var fields []string
+	for name, value := range old {
+		if !equal(value, current[name]) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range current {
+		if _, ok := old[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// equal compares decoded JSON values. encoding/json writes map keys in
+// sorted order, so equal values marshal to identical bytes.
+func equal(a, b interface{}) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+func join(scope, key string) string {
+	if scope == "" {
+		return key
+	}
+	return scope + "/" + key
+}
+
+func joinType(typ, name string) string {
+	if typ == "" {
+		return name
+	}
+	return typ + "." + name
+}
+
+func less(a, b Resource) bool {
+	if a.Platform != b.Platform {
+		return a.Platform < b.Platform
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Key < b.Key
+}
+
+func sortResources(resources []Resource) {
+	sort.Slice(resources, func(i, j int) bool { return less(resources[i], resources[j]) })
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// document builds a collector.Document from inventories written as JSON.
+func document(t *testing.T, inventories map[string]string) collector.Document {
+	t.Helper()
+	doc := collector.Document{Inventories: map[string]collector.Inventory{}}
+	for name, inventory := range inventories {
+		if !json.Valid([]byte(inventory)) {
+			t.Fatalf("%s inventory is not valid JSON", name)
+		}
+		doc.Inventories[name] = json.RawMessage(inventory)
+	}
+	return doc
+}
+
+func TestIdentity(t *testing.T) {
+	tests := []struct {
+		name   string
+		object map[string]interface{}
+		want   string
+	}{
+		{"arn wins", map[string]interface{}{"arn": "arn:aws:s3:::b", "id": "x", "name": "b"}, "arn:aws:s3:::b"},
+		{"id", map[string]interface{}{"id": "/subscriptions/s/vm", "name": "vm"}, "/subscriptions/s/vm"},
+		{"region and instance", map[string]interface{}{"region": "eu-west-1", "instanceID": "i-1"}, "eu-west-1/i-1"},
+		{"instance without region", map[string]interface{}{"instanceID": "i-1"}, "i-1"},
+		{"region and volume", map[string]interface{}{"region": "us-east-1", "volumeID": "vol-1"}, "us-east-1/vol-1"},
+		{"namespace and name", map[string]interface{}{"namespace": "default", "name": "web"}, "default/web"},
+		{"cluster-scoped name", map[string]interface{}{"name": "node-1"}, "node-1"},
+		{"namespace only", map[string]interface{}{"namespace": "default"}, "default"},
+		{"non-string identity", map[string]interface{}{"id": 5}, ""},
+		{"none", map[string]interface{}{"from": map[string]interface{}{}, "to": map[string]interface{}{}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := identity(tt.object); got != tt.want {
+				t.Errorf("identity = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectsScopes(t *testing.T) {
+	doc := document(t, map[string]string{
+		"kubernetes": `{"clusters": {
+			"prod": {"pods": [{"namespace": "default", "name": "web"}], "namespaces": ["default"]},
+			"dev": {"pods": [{"namespace": "default", "name": "web"}]}
+		}}`,
+		"aws":   `{"accounts": {"111": {"ec2Instances": [{"region": "eu-west-1", "instanceID": "i-1"}]}}}`,
+		"veeam": `{"BackupJobs": [{"id": "job-1"}]}`,
+	})
+	objects, err := Objects(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, object := range objects {
+		got = append(got, object.Platform+" "+object.Type+" "+object.Key+" "+object.Scope+" "+object.ScopeKind)
+	}
+	sort.Strings(got)
+	want := []string{
+		"aws ec2Instances 111/eu-west-1/i-1 111 account",
+		"kubernetes namespaces prod/default prod cluster",
+		"kubernetes pods dev/default/web dev cluster",
+		"kubernetes pods prod/default/web prod cluster",
+		"veeam BackupJobs job-1  ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Objects =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestObjectsSkipsItemsWithoutIdentity(t *testing.T) {
+	doc := document(t, map[string]string{
+		"kubernetes": `{
+			"pods": [{"foo": 1}, {"namespace": "default", "name": "web"}, 5, null],
+			"edges": [{"from": {"kind": "Pod"}, "to": {"kind": "Node"}}]
+		}`,
+	})
+	objects, err := Objects(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Type != "pods" || objects[0].Key != "default/web" {
+		t.Errorf("Objects = %+v, want only pods default/web", objects)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	from := document(t, map[string]string{
+		"kubernetes": `{"pods": [
+			{"namespace": "default", "name": "web", "status": "Running", "node": "a"},
+			{"namespace": "default", "name": "old", "status": "Running"}
+		]}`,
+		"aws": `{"s3Buckets": [{"arn": "arn:aws:s3:::b", "immutable": false}]}`,
+	})
+	to := document(t, map[string]string{
+		"kubernetes": `{"pods": [
+			{"namespace": "default", "name": "web", "status": "Pending", "restarts": 1, "node": "a"},
+			{"namespace": "default", "name": "new", "status": "Running"}
+		]}`,
+		"aws": `{"s3Buckets": [{"arn": "arn:aws:s3:::b", "immutable": false}]}`,
+	})
+	diff, err := Compare(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Diff{
+		Added:   []Resource{{Platform: "kubernetes", Type: "pods", Key: "default/new"}},
+		Removed: []Resource{{Platform: "kubernetes", Type: "pods", Key: "default/old"}},
+		Changed: []Change{{Resource: Resource{Platform: "kubernetes", Type: "pods", Key: "default/web"}, Fields: []string{"restarts", "status"}}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Compare = %+v, want %+v", diff, want)
+	}
+
+	same, err := Compare(from, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same.Empty() {
+		t.Errorf("Compare of a run with itself = %+v", same)
+	}
+}
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// DriftRule names the fields of one resource type whose values are
+// expected to stay as they were in the baseline.
+type DriftRule struct {
+	Platform string
+	Type     string
+	Fields   []string
+}
+
+// DefaultDriftRules track image tags, replica counts and storage classes in
+// Kubernetes, EC2 instance types and S3 bucket immutability in AWS, and
+// Veeam job schedules.
+var DefaultDriftRules = []DriftRule{
+	{Platform: "kubernetes", Type: "deployments", Fields: []string{"images", "replicas"}},
+	{Platform: "kubernetes", Type: "statefulSets", Fields: []string{"image", "replicas"}},
+	{Platform: "kubernetes", Type: "daemonSets", Fields: []string{"images"}},
+	{Platform: "kubernetes", Type: "cronJobs", Fields: []string{"schedule"}},
+	{Platform: "kubernetes", Type: "persistentVolumeClaims", Fields: []string{"storageClass"}},
+	{Platform: "kubernetes", Type: "persistentVolumes", Fields: []string{"storageClass"}},
+	{Platform: "aws", Type: "ec2Instances", Fields: []string{"type"}},
+	{Platform: "aws", Type: "s3Buckets", Fields: []string{"immutable"}},
+	{Platform: "veeam", Type: "BackupJobs", Fields: []string{"schedule"}},
+}
+
+// ParseDriftRules applies specs of the form "platform/type:field,field" to
+// rules. A spec replaces the tracked fields of its type, or adds the type
+// if rules have none; a spec without fields stops tracking the type.
+func ParseDriftRules(rules []DriftRule, specs []string) ([]DriftRule, error) {
+	out := append([]DriftRule(nil), rules...)
+	for _, spec := range specs {
+		resource, list, ok := strings.Cut(spec, ":")
+		platform, typ, _ := strings.Cut(resource, "/")
+		if !ok || platform == "" || typ == "" {
+			return nil, fmt.Errorf("invalid drift fields %q, expected platform/type:field,field", spec)
+		}
+		var fields []string
+		for _, field := range strings.Split(list, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+		rule := DriftRule{Platform: platform, Type: typ, Fields: fields}
+		i := 0
+		for ; i < len(out); i++ {
+			if out[i].Platform == platform && out[i].Type == typ {
+				break
+			}
+		}
+		switch {
+		case i == len(out) && len(fields) > 0:
+			out = append(out, rule)
+		case i < len(out) && len(fields) > 0:
+			out[i] = rule
+		case i < len(out):
+			out = append(out[:i], out[i+1:]...)
+		}
+	}
+	return out, nil
+}
+
+// Drift is a tracked field whose live value differs from the baseline. A
+// baseline resource that no longer exists is reported with an empty Field.
+type Drift struct {
+	Resource
+	Field    string      `json:"field,omitempty"`
+	Baseline interface{} `json:"baseline,omitempty"`
+	Current  interface{} `json:"current,omitempty"`
+	Allowed  bool        `json:"allowed"`
+}
+
+// DetectDrift compares the fields tracked by rules between baseline and
+// current. Only platforms present in both documents are compared, so a
+// baseline may cover more platforms than a single live collection.
+func DetectDrift(baseline, current collector.Document, rules []DriftRule) ([]Drift, error) {
+	before, err := resources(baseline)
+	if err != nil {
+		return nil, err
+	}
+	after, err := resources(current)
+	if err != nil {
+		return nil, err
+	}
+	tracked := map[[2]string][]string{}
+	for _, rule := range rules {
+		tracked[[2]string{rule.Platform, rule.Type}] = rule.Fields
+	}
+	var drifts []Drift
+	for r, old := range before {
+		fields, ok := tracked[[2]string{r.Platform, r.Type}]
+		if !ok || current.Inventories[r.Platform] == nil {
+			continue
+		}
+		live, ok := after[r]
+		if !ok {
+			drifts = append(drifts, Drift{Resource: r})
+			continue
+		}
+		for _, field := range fields {
+			if !equal(old[field], live[field]) {
+				drifts = append(drifts, Drift{Resource: r, Field: field, Baseline: old[field], Current: live[field]})
+			}
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Resource != drifts[j].Resource {
+			return less(drifts[i].Resource, drifts[j].Resource)
+		}
+		return drifts[i].Field < drifts[j].Field
+	})
+	return drifts, nil
+}
+
+// AllowList holds patterns of drift that is accepted. Each pattern has the
+// form "platform:type:key:field", where * matches any run of characters,
+// e.g. "kubernetes:deployments:default/*:replicas". The field of a missing
+// resource is empty.
+type AllowList []*regexp.Regexp
+
+// ParseAllowList compiles patterns. Blank patterns and patterns starting
+// with # are ignored so that allow-list files can hold comments.
+func ParseAllowList(patterns []string) (AllowList, error) {
+	var list AllowList
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if strings.Count(pattern, ":") != 3 {
+			return nil, fmt.Errorf("invalid allow-list pattern %q, expected platform:type:key:field", pattern)
+		}
+		expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+		list = append(list, regexp.MustCompile("^"+expr+"$"))
+	}
+	return list, nil
+}
+
+// Allows reports whether d matches one of the patterns.
+func (a AllowList) Allows(d Drift) bool {
+	s := strings.Join([]string{d.Platform, d.Type, d.Key, d.Field}, ":")
+	for _, re := range a {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteDrift writes drifts grouped by platform, marking allowed ones.
+func WriteDrift(w io.Writer, drifts []Drift) {
+	if len(drifts) == 0 {
+		fmt.Fprintln(w, "No drift")
+		return
+	}
+	platform := ""
+	for _, d := range drifts {
+		if d.Platform != platform {
+			platform = d.Platform
+			fmt.Fprintln(w, platform)
+		}
+		change := "missing"
+		if d.Field != "" {
+			change = fmt.Sprintf("%s: %s -> %s", d.Field, jsonText(d.Baseline), jsonText(d.Current))
+		}
+		allowed := ""
+		if d.Allowed {
+			allowed = " (allowed)"
+		}
+		fmt.Fprintf(w, "  %s %s %s%s\n", d.Type, d.Key, change, allowed)
+	}
+}
+
+func jsonText(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	baseline := document(t, map[string]string{
+		"kubernetes": `{
+			"deployments": [
+				{"namespace": "default", "name": "web", "images": ["web:1"], "replicas": 2, "ready": 2},
+				{"namespace": "default", "name": "gone", "images": ["gone:1"], "replicas": 1}
+			],
+			"pods": [{"namespace": "default", "name": "web-1", "status": "Running"}]
+		}`,
+		"aws": `{"ec2Instances": [{"region": "eu-west-1", "instanceID": "i-1", "type": "t3.micro"}]}`,
+	})
+	current := document(t, map[string]string{
+		"kubernetes": `{
+			"deployments": [{"namespace": "default", "name": "web", "images": ["web:2"], "replicas": 2, "ready": 1}],
+			"pods": [{"namespace": "default", "name": "web-1", "status": "Failed"}]
+		}`,
+	})
+	drifts, err := DetectDrift(baseline, current, DefaultDriftRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Untracked fields and types are ignored, and aws was not collected.
+	want := []Drift{
+		{Resource: Resource{Platform: "kubernetes", Type: "deployments", Key: "default/gone"}},
+		{
+			Resource: Resource{Platform: "kubernetes", Type: "deployments", Key: "default/web"},
+			Field:    "images",
+			Baseline: []interface{}{"web:1"},
+			Current:  []interface{}{"web:2"},
+		},
+	}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Errorf("DetectDrift = %+v, want %+v", drifts, want)
+	}
+}
+
+func TestAllowList(t *testing.T) {
+	list, err := ParseAllowList([]string{
+		"# comment",
+		"",
+		"kubernetes:deployments:default/*:replicas",
+		"aws:ec2Instances:*:type",
+		"kubernetes:pods:a.b:",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("ParseAllowList returned %d patterns, want 3", len(list))
+	}
+	tests := []struct {
+		drift Drift
+		want  bool
+	}{
+		{Drift{Resource: Resource{Platform: "kubernetes", Type: "deployments", Key: "default/web"}, Field: "replicas"}, true},
+		{Drift{Resource: Resource{Platform: "kubernetes", Type: "deployments", Key: "prod/web"}, Field: "replicas"}, false},
+		{Drift{Resource: Resource{Platform: "kubernetes", Type: "deployments", Key: "default/web"}, Field: "images"}, false},
+		{Drift{Resource: Resource{Platform: "aws", Type: "ec2Instances", Key: "eu-west-1/i-1"}, Field: "type"}, true},
+		// Dots are literal and a missing resource has an empty field.
+		{Drift{Resource: Resource{Platform: "kubernetes", Type: "pods", Key: "a.b"}}, true},
+		{Drift{Resource: Resource{Platform: "kubernetes", Type: "pods", Key: "axb"}}, false},
+	}
+	for _, tt := range tests {
+		if got := list.Allows(tt.drift); got != tt.want {
+			t.Errorf("Allows(%+v) = %v, want %v", tt.drift, got, tt.want)
+		}
+	}
+
+	if _, err := ParseAllowList([]string{"kubernetes:deployments:replicas"}); err == nil {
+		t.Error("ParseAllowList accepted a pattern without four parts")
+	}
+}
+
+func TestParseDriftRules(t *testing.T) {
+	defaults := []DriftRule{
+		{Platform: "kubernetes", Type: "deployments", Fields: []string{"images", "replicas"}},
+		{Platform: "kubernetes", Type: "cronJobs", Fields: []string{"schedule"}},
+	}
+	rules, err := ParseDriftRules(defaults, []string{
+		"kubernetes/deployments:images",
+		"kubernetes/cronJobs:",
+		"kubernetes/velero.backups:phase, storageLocation",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DriftRule{
+		{Platform: "kubernetes", Type: "deployments", Fields: []string{"images"}},
+		{Platform: "kubernetes", Type: "velero.backups", Fields: []string{"phase", "storageLocation"}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("ParseDriftRules = %+v, want %+v", rules, want)
+	}
+	if len(defaults[0].Fields) != 2 || len(defaults) != 2 {
+		t.Errorf("ParseDriftRules modified the defaults: %+v", defaults)
+	}
+
+	for _, spec := range []string{"kubernetes/deployments", "deployments:images", "/deployments:images", "kubernetes/:images"} {
+		if _, err := ParseDriftRules(defaults, []string{spec}); err == nil {
+			t.Errorf("ParseDriftRules accepted %q", spec)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package history keeps every collection run in a local snapshot store and
+// compares runs with each other.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+// idLayout formats run IDs from the collection time so that IDs sort
+// chronologically.
+const idLayout = "20060102T150405Z"
+
+// Run describes a stored collection run.
+type Run struct {
+	ID          string                      `json:"id"`
+	CollectedAt time.Time                   `json:"collectedAt"`
+	ToolVersion string                      `json:"toolVersion,omitempty"`
+	Inventories []string                    `json:"inventories"`
+	Sources     map[string]collector.Source `json:"sources,omitempty"`
+}
+
+// Store is a directory holding one JSON document per run, named after the
+// run ID, and next to each a small <id>.meta file with its Run so runs can
+// be listed without decoding every document.
+type Store struct {
+	Dir string
+}
+
+// Open returns the store in dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("no snapshot store directory given")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create snapshot store: %v", err)
+	}
+	return &Store{Dir: dir}, nil
+}
+
+// Save writes doc as a new run. The file is written under a temporary name
+// and then linked to its run ID, which fails rather than overwrites if a
+// concurrent Save took the ID first, so a partially written run is never
+// listed and no run is lost.
+func (s *Store) Save(doc collector.Document) (Run, error) {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return Run{}, err
+	}
+	tmp, err := writeTemp(s.Dir, b)
+	if err != nil {
+		return Run{}, err
+	}
+	defer os.Remove(tmp)
+
+	base := doc.CollectedAt.UTC().Format(idLayout)
+	id := base
+	for i := 2; ; i++ {
+		err := os.Link(tmp, s.path(id))
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return Run{}, err
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	run := runOf(id, doc)
+	// Without its .meta file List reads the document itself instead.
+	s.writeMeta(run)
+	return run, nil
+}
+
+// List returns every stored run, oldest first.
+func (s *Store) List() ([]Run, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]Run, 0, len(ids))
+	for _, id := range ids {
+		run, err := s.readMeta(id)
+		if err != nil {
+			// Runs saved before .meta files existed, or whose .meta
+			// could not be written, are read in full once.
+			doc, err := s.Load(id)
+			if err != nil {
+				return nil, fmt.Errorf("run %s: %v", id, err)
+			}
+			run = runOf(id, doc)
+			s.writeMeta(run)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// ids returns the IDs of the stored runs in order, from the file names
+// alone.
+func (s *Store) ids() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *Store) readMeta(id string) (Run, error) {
+	var run Run
+	b, err := os.ReadFile(s.metaPath(id))
+	if err != nil {
+		return run, err
+	}
+	if err := json.Unmarshal(b, &run); err != nil {
+		return run, err
+	}
+	if run.ID != id {
+		return run, fmt.Errorf("%s describes run %s", s.metaPath(id), run.ID)
+	}
+	return run, nil
+}
+
+func (s *Store) writeMeta(run Run) error {
+	b, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	tmp, err := writeTemp(s.Dir, b)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	return os.Rename(tmp, s.metaPath(run.ID))
+}
+
+// writeTemp writes b to a new hidden file in dir and returns its name.
+func writeTemp(dir string, b []byte) (string, error) {
+	tmp, err := os.CreateTemp(dir, ".run-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// Load reads the document of the run with the given ID.
+func (s *Store) Load(id string) (collector.Document, error) {
+	return ReadFile(s.path(id))
+}
+
+// Resolve finds the run a reference names: a run ID, a unique prefix of
+// one, "latest", or "latest~N" for the Nth run before the latest.
+func (s *Store) Resolve(ref string) (string, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("snapshot store %s has no runs", s.Dir)
+	}
+	if ref == "latest" || strings.HasPrefix(ref, "latest~") {
+		back := 0
+		if n := strings.TrimPrefix(ref, "latest"); n != "" {
+			if _, err := fmt.Sscanf(n, "~%d", &back); err != nil || back < 0 {
+				return "", fmt.Errorf("invalid run reference %q", ref)
+			}
+		}
+		if back >= len(ids) {
+			return "", fmt.Errorf("run reference %q is older than the %d stored runs", ref, len(ids))
+		}
+		return ids[len(ids)-1-back], nil
+	}
+	var matches []string
+	for _, id := range ids {
+		if id == ref {
+			return id, nil
+		}
+		if strings.HasPrefix(id, ref) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no run matches %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("run reference %q is ambiguous: %s", ref, strings.Join(matches, ", "))
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+func (s *Store) metaPath(id string) string {
+	return filepath.Join(s.Dir, id+".meta")
+}
+
+// ReadFile reads a document saved by kollect, e.g. with --output.
+func ReadFile(name string) (collector.Document, error) {
+	var doc collector.Document
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return doc, err
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return doc, fmt.Errorf("%s: %v", name, err)
+	}
+	return doc, nil
+}
+
+func runOf(id string, doc collector.Document) Run {
+	run := Run{
+		ID:          id,
+		CollectedAt: doc.CollectedAt,
+		ToolVersion: doc.ToolVersion,
+		Sources:     doc.Sources,
+	}
+	for name := range doc.Inventories {
+		run.Inventories = append(run.Inventories, name)
+	}
+	sort.Strings(run.Inventories)
+	return run
+}
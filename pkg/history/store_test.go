@@ -0,0 +1,122 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+func TestStoreSaveConcurrent(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	const n = 8
+	ids := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			run, err := store.Save(collector.Document{CollectedAt: at, ToolVersion: "v1"})
+			ids[i], errs[i] = run.ID, err
+		}(i)
+	}
+	wg.Wait()
+	seen := map[string]bool{}
+	for i := range ids {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if seen[ids[i]] {
+			t.Fatalf("run ID %s saved twice", ids[i])
+		}
+		seen[ids[i]] = true
+	}
+	runs, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != n {
+		t.Fatalf("List returned %d runs, want %d", len(runs), n)
+	}
+	if runs[0].ID != "20250102T030405Z" {
+		t.Errorf("first run is %s", runs[0].ID)
+	}
+}
+
+func TestStoreListWithoutMeta(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := collector.Document{
+		CollectedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		ToolVersion: "v1",
+		Inventories: map[string]collector.Inventory{"aws": nil},
+	}
+	run, err := store.Save(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(store.metaPath(run.ID)); err != nil {
+		t.Fatal(err)
+	}
+	runs, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].ToolVersion != "v1" || len(runs[0].Inventories) != 1 {
+		t.Fatalf("List returned %+v", runs)
+	}
+	if _, err := os.Stat(store.metaPath(run.ID)); err != nil {
+		t.Errorf("meta file not rewritten: %v", err)
+	}
+
+	// A stale .meta file must not be trusted.
+	if err := os.WriteFile(filepath.Join(store.Dir, run.ID+".meta"), []byte(`{"id":"other"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runs, err = store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs[0].ID != run.ID || runs[0].ToolVersion != "v1" {
+		t.Errorf("List returned %+v", runs[0])
+	}
+}
+
+func TestStoreResolve(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, day := range []int{1, 2, 3} {
+		if _, err := store.Save(collector.Document{CollectedAt: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		ref, want string
+		err       bool
+	}{
+		{ref: "latest", want: "20250103T000000Z"},
+		{ref: "latest~2", want: "20250101T000000Z"},
+		{ref: "latest~3", err: true},
+		{ref: "20250102", want: "20250102T000000Z"},
+		{ref: "2025", err: true},
+		{ref: "2024", err: true},
+	}
+	for _, tt := range tests {
+		got, err := store.Resolve(tt.ref)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v", tt.ref, got, err)
+		}
+	}
+}
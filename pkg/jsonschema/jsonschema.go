@@ -0,0 +1,265 @@
+// Package jsonschema generates JSON Schema (draft 2020-12) documents from Go
+// types by reflection, following encoding/json's rules for field names,
+// omitempty and embedded structs.
+package jsonschema
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect generated schemas declare.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                string             `json:"const,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Reflector turns Go types into schemas. Named struct types whose package
+// path starts with one of Packages become entries in Defs and are
+// referenced by name; struct types from other packages, such as cloud SDK
+// models, are described as plain objects.
+type Reflector struct {
+	Packages []string
+	Defs     map[string]*Schema
+
+	names map[reflect.Type]string
+}
+
+// Reflect returns the schema for the type of v.
+func (r *Reflector) Reflect(v interface{}) *Schema {
+	return r.reflect(reflect.TypeOf(v))
+}
+
+func (r *Reflector) reflect(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return r.reflect(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.reflect(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflect(t.Elem())}
+	case reflect.Struct:
+		if !r.owned(t) {
+			return &Schema{Type: "object"}
+		}
+		name, ok := r.names[t]
+		if !ok {
+			name = r.defName(t)
+			// Reserve the name first so recursive types terminate.
+			r.names[t] = name
+			r.Defs[name] = &Schema{}
+			*r.Defs[name] = *r.reflectStruct(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	// Interfaces and anything else accept any value.
+	return &Schema{}
+}
+
+// defName names the definition for t after the type, qualified with its
+// package name when two packages define a type of the same name.
+func (r *Reflector) defName(t reflect.Type) string {
+	if r.Defs == nil {
+		r.Defs = map[string]*Schema{}
+	}
+	if r.names == nil {
+		r.names = map[reflect.Type]string{}
+	}
+	name := t.Name()
+	if _, taken := r.Defs[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	return name
+}
+
+func (r *Reflector) owned(t reflect.Type) bool {
+	if t.Name() == "" {
+		return false
+	}
+	for _, pkg := range r.Packages {
+		if strings.HasPrefix(t.PkgPath(), pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reflector) reflectStruct(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range structFields(t) {
+		property := r.reflect(f.typ)
+		if !f.omitEmpty {
+			if !f.optional {
+				s.Required = append(s.Required, f.name)
+			}
+			// Nil slices, maps and pointers are written as null.
+			switch f.typ.Kind() {
+			case reflect.Slice, reflect.Map, reflect.Pointer:
+				if f.typ != rawMessageType {
+					property = &Schema{OneOf: []*Schema{property, {Type: "null"}}}
+				}
+			}
+		}
+		s.Properties[f.name] = property
+	}
+	return s
+}
+
+// structField is a property of a struct as encoding/json writes it.
+type structField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+	// optional fields are promoted through an embedded pointer and are
+	// left out when it is nil.
+	optional bool
+}
+
+// structFields returns the properties encoding/json writes for t in field
+// order, with the fields of embedded structs promoted. Of several fields
+// with the same name the least nested wins, then a tagged one; if that
+// leaves more than one, the name is left out, as encoding/json does.
+func structFields(t reflect.Type) []structField {
+	var all []structField
+	visited := map[reflect.Type]bool{}
+	var walk func(t reflect.Type, index []int, optional bool)
+	walk = func(t reflect.Type, index []int, optional bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			ft := field.Type
+			if field.Anonymous {
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if !field.IsExported() && ft.Kind() != reflect.Struct {
+					continue
+				}
+			} else if !field.IsExported() {
+				continue
+			}
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			fieldIndex := append(index[:len(index):len(index)], i)
+			if name == "" && field.Anonymous && ft.Kind() == reflect.Struct {
+				walk(ft, fieldIndex, optional || field.Type.Kind() == reflect.Pointer)
+				continue
+			}
+			sf := structField{
+				name:      name,
+				index:     fieldIndex,
+				typ:       field.Type,
+				tagged:    name != "",
+				omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+				optional:  optional,
+			}
+			if sf.name == "" {
+				sf.name = field.Name
+			}
+			all = append(all, sf)
+		}
+	}
+	walk(t, nil, false)
+
+	byName := map[string][]structField{}
+	for _, f := range all {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	var fields []structField
+	for _, candidates := range byName {
+		if f, ok := dominantField(candidates); ok {
+			fields = append(fields, f)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+// dominantField picks the field encoding/json writes among fields sharing a
+// name, reporting false if none is.
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields {
+		if len(f.index) < depth {
+			depth = len(f.index)
+		}
+	}
+	var shallowest, tagged []structField
+	for _, f := range fields {
+		if len(f.index) == depth {
+			shallowest = append(shallowest, f)
+			if f.tagged {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+	switch {
+	case len(tagged) == 1:
+		return tagged[0], true
+	case len(tagged) == 0 && len(shallowest) == 1:
+		return shallowest[0], true
+	}
+	return structField{}, false
+}
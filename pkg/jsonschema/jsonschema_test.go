@@ -0,0 +1,134 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type base struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Shade string
+}
+
+type Extra struct {
+	Note    string `json:"note,omitempty"`
+	Comment string
+	Shade   string
+}
+
+type Other struct {
+	Comment string
+}
+
+type sample struct {
+	base
+	*Extra
+	Other
+	Title   string            `json:"name"`
+	Count   int               `json:"count,omitempty"`
+	Created time.Time         `json:"created"`
+	Parent  *sample           `json:"parent,omitempty"`
+	Labels  map[string]string `json:"labels"`
+	Tags    []string          `json:"tags"`
+	Limit   *int              `json:"limit"`
+	Raw     json.RawMessage   `json:"raw"`
+	Skipped string            `json:"-"`
+	hidden  string
+}
+
+func TestReflectStruct(t *testing.T) {
+	r := &Reflector{Packages: []string{"github.com/michaelcade/kollect/pkg/jsonschema"}}
+	if got := r.Reflect(sample{}); got.Ref != "#/$defs/sample" {
+		t.Fatalf("Reflect = %+v, want a reference to sample", got)
+	}
+	def := r.Defs["sample"]
+	if def == nil {
+		t.Fatal("sample is not defined")
+	}
+	required := map[string]bool{}
+	for _, name := range def.Required {
+		required[name] = true
+	}
+
+	tests := []struct {
+		property string
+		want     string
+		required bool
+	}{
+		{property: "id", want: `{"type":"string"}`, required: true},
+		// The outer field shadows the promoted one.
+		{property: "name", want: `{"type":"string"}`, required: true},
+		// Promoted through a pointer, so left out when it is nil.
+		{property: "note", want: `{"type":"string"}`},
+		{property: "count", want: `{"type":"integer"}`},
+		{property: "created", want: `{"type":"string","format":"date-time"}`, required: true},
+		{property: "parent", want: `{"$ref":"#/$defs/sample"}`},
+		{property: "labels", want: `{"oneOf":[{"type":"object","additionalProperties":{"type":"string"}},{"type":"null"}]}`, required: true},
+		{property: "tags", want: `{"oneOf":[{"type":"array","items":{"type":"string"}},{"type":"null"}]}`, required: true},
+		{property: "limit", want: `{"oneOf":[{"type":"integer"},{"type":"null"}]}`, required: true},
+		{property: "raw", want: `{}`, required: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			property, ok := def.Properties[tt.property]
+			if !ok {
+				t.Fatal("property missing")
+			}
+			b, err := json.Marshal(property)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("schema = %s, want %s", b, tt.want)
+			}
+			if required[tt.property] != tt.required {
+				t.Errorf("required = %v, want %v", required[tt.property], tt.required)
+			}
+		})
+	}
+
+	// Comment and Shade are ambiguous at the same depth.
+	for _, name := range []string{"Comment", "Shade", "Skipped", "hidden", "base", "Extra", "Other"} {
+		if _, ok := def.Properties[name]; ok {
+			t.Errorf("unexpected property %s", name)
+		}
+	}
+}
+
+func TestReflectMatchesEncodingJSON(t *testing.T) {
+	r := &Reflector{Packages: []string{"github.com/michaelcade/kollect/pkg/jsonschema"}}
+	r.Reflect(sample{})
+	var properties []string
+	for name := range r.Defs["sample"].Properties {
+		properties = append(properties, name)
+	}
+	sort.Strings(properties)
+
+	limit := 1
+	b, err := json.Marshal(sample{
+		Extra:  &Extra{Note: "n"},
+		Count:  1,
+		Parent: &sample{},
+		Limit:  &limit,
+		Raw:    json.RawMessage(`1`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(b, &object); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, properties) {
+		t.Errorf("encoding/json writes %q, schema has %q", keys, properties)
+	}
+}
@@ -0,0 +1,378 @@
+package kollect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// kastenNamespaceLabel is the label K10 policies select application
+// namespaces by.
+const kastenNamespaceLabel = "k10.kasten.io/appNamespace"
+
+// kastenRetentionPeriods orders the retention counts of a K10 policy.
+var kastenRetentionPeriods = []string{"hourly", "daily", "weekly", "monthly", "yearly"}
+
+func backupTasks(data *k8sdata.K8sData, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, opts Options) []fetchTask {
+	return []fetchTask{
+		{"Velero", func(ctx context.Context) (err error) {
+			data.Velero, err = fetchVelero(ctx, discoveryClient, dynamicClient, opts)
+			return err
+		}},
+		{"Kasten", func(ctx context.Context) (err error) {
+			data.Kasten, err = fetchKasten(ctx, discoveryClient, dynamicClient, opts)
+			return err
+		}},
+	}
+}
+
+// groupVersionServed reports whether the API server serves groupVersion,
+// which is how backup tools are detected.
+func groupVersionServed(discoveryClient discovery.DiscoveryInterface, groupVersion string) (bool, error) {
+	_, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// eachUnstructured lists gvr across all namespaces. The namespace and label
+// scoping is deliberately not applied: backup tools keep their objects in
+// their own namespace whichever namespaces they protect.
+func (o Options) eachUnstructured(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, fn func(item *unstructured.Unstructured)) error {
+	err := o.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return dynamicClient.Resource(gvr).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		fn(obj.(*unstructured.Unstructured))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", gvr.GroupResource(), err)
+	}
+	return nil
+}
+
+func fetchVelero(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, opts Options) (*k8sdata.VeleroInfo, error) {
+	installed, err := groupVersionServed(discoveryClient, "velero.io/v1")
+	if err != nil || !installed {
+		return nil, err
+	}
+	gvr := func(resource string) schema.GroupVersionResource {
+		return schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: resource}
+	}
+
+	velero := &k8sdata.VeleroInfo{}
+	errs := []error{
+		opts.eachUnstructured(ctx, dynamicClient, gvr("backups"), func(item *unstructured.Unstructured) {
+			backup := k8sdata.VeleroBackupInfo{
+				Name:              item.GetName(),
+				Namespace:         item.GetNamespace(),
+				CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+				Schedule:          item.GetLabels()["velero.io/schedule-name"],
+			}
+			backup.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+			backup.IncludedNamespaces, backup.ExcludedNamespaces = veleroNamespaces(item.Object, "spec")
+			backup.StorageLocation, _, _ = unstructured.NestedString(item.Object, "spec", "storageLocation")
+			backup.TTL, _, _ = unstructured.NestedString(item.Object, "spec", "ttl")
+			backup.StartTimestamp, _, _ = unstructured.NestedString(item.Object, "status", "startTimestamp")
+			backup.CompletionTimestamp, _, _ = unstructured.NestedString(item.Object, "status", "completionTimestamp")
+			backup.Expiration, _, _ = unstructured.NestedString(item.Object, "status", "expiration")
+			velero.Backups = append(velero.Backups, backup)
+		}),
+		opts.eachUnstructured(ctx, dynamicClient, gvr("schedules"), func(item *unstructured.Unstructured) {
+			schedule := k8sdata.VeleroScheduleInfo{
+				Name:              item.GetName(),
+				Namespace:         item.GetNamespace(),
+				CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+			}
+			schedule.Schedule, _, _ = unstructured.NestedString(item.Object, "spec", "schedule")
+			schedule.Paused, _, _ = unstructured.NestedBool(item.Object, "spec", "paused")
+			schedule.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+			schedule.IncludedNamespaces, schedule.ExcludedNamespaces = veleroNamespaces(item.Object, "spec", "template")
+			schedule.StorageLocation, _, _ = unstructured.NestedString(item.Object, "spec", "template", "storageLocation")
+			schedule.TTL, _, _ = unstructured.NestedString(item.Object, "spec", "template", "ttl")
+			schedule.LastBackup, _, _ = unstructured.NestedString(item.Object, "status", "lastBackup")
+			velero.Schedules = append(velero.Schedules, schedule)
+		}),
+		opts.eachUnstructured(ctx, dynamicClient, gvr("backupstoragelocations"), func(item *unstructured.Unstructured) {
+			location := k8sdata.VeleroBackupStorageLocationInfo{
+				Name:              item.GetName(),
+				Namespace:         item.GetNamespace(),
+				CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+			}
+			location.Provider, _, _ = unstructured.NestedString(item.Object, "spec", "provider")
+			location.Bucket, _, _ = unstructured.NestedString(item.Object, "spec", "objectStorage", "bucket")
+			location.Prefix, _, _ = unstructured.NestedString(item.Object, "spec", "objectStorage", "prefix")
+			location.Default, _, _ = unstructured.NestedBool(item.Object, "spec", "default")
+			location.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+			location.LastValidationTime, _, _ = unstructured.NestedString(item.Object, "status", "lastValidationTime")
+			velero.BackupStorageLocations = append(velero.BackupStorageLocations, location)
+		}),
+		opts.eachUnstructured(ctx, dynamicClient, gvr("volumesnapshotlocations"), func(item *unstructured.Unstructured) {
+			location := k8sdata.VeleroVolumeSnapshotLocationInfo{
+				Name:              item.GetName(),
+				Namespace:         item.GetNamespace(),
+				CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+			}
+			location.Provider, _, _ = unstructured.NestedString(item.Object, "spec", "provider")
+			velero.VolumeSnapshotLocations = append(velero.VolumeSnapshotLocations, location)
+		}),
+	}
+	return velero, errors.Join(errs...)
+}
+
+// veleroNamespaces reads includedNamespaces and excludedNamespaces under
+// fields. Velero treats an empty include list as every namespace.
+func veleroNamespaces(obj map[string]interface{}, fields ...string) (included, excluded []string) {
+	included, _, _ = unstructured.NestedStringSlice(obj, append(fields, "includedNamespaces")...)
+	excluded, _, _ = unstructured.NestedStringSlice(obj, append(fields, "excludedNamespaces")...)
+	if len(included) == 0 {
+		included = []string{"*"}
+	}
+	return included, excluded
+}
+
+func fetchKasten(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, opts Options) (*k8sdata.KastenInfo, error) {
+	installed, err := groupVersionServed(discoveryClient, "config.kio.kasten.io/v1alpha1")
+	if err != nil || !installed {
+		return nil, err
+	}
+	config := func(resource string) schema.GroupVersionResource {
+		return schema.GroupVersionResource{Group: "config.kio.kasten.io", Version: "v1alpha1", Resource: resource}
+	}
+
+	kasten := &k8sdata.KastenInfo{}
+	errs := []error{
+		opts.eachUnstructured(ctx, dynamicClient, config("policies"), func(item *unstructured.Unstructured) {
+			policy := k8sdata.KastenPolicyInfo{
+				Name:              item.GetName(),
+				Namespace:         item.GetNamespace(),
+				CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+			}
+			policy.Frequency, _, _ = unstructured.NestedString(item.Object, "spec", "frequency")
+			policy.Paused, _, _ = unstructured.NestedBool(item.Object, "spec", "paused")
+			policy.Validation, _, _ = unstructured.NestedString(item.Object, "status", "validation")
+			actions, _, _ := unstructured.NestedSlice(item.Object, "spec", "actions")
+			for _, action := range actions {
+				if a, ok := action.(map[string]interface{}); ok {
+					if name, ok := a["action"].(string); ok {
+						policy.Actions = append(policy.Actions, name)
+					}
+				}
+			}
+			policy.IncludedNamespaces, policy.ExcludedNamespaces = kastenNamespaces(item.Object)
+			retention, _, _ := unstructured.NestedMap(item.Object, "spec", "retention")
+			for period, count := range retention {
+				if n, ok := count.(int64); ok {
+					if policy.Retention == nil {
+						policy.Retention = map[string]int64{}
+					}
+					policy.Retention[period] = n
+				}
+			}
+			kasten.Policies = append(kasten.Policies, policy)
+		}),
+		opts.eachUnstructured(ctx, dynamicClient, config("profiles"), func(item *unstructured.Unstructured) {
+			profile := k8sdata.KastenProfileInfo{
+				Name:              item.GetName(),
+				Namespace:         item.GetNamespace(),
+				CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+			}
+			profile.Type, _, _ = unstructured.NestedString(item.Object, "spec", "type")
+			profile.LocationType, _, _ = unstructured.NestedString(item.Object, "spec", "locationSpec", "type")
+			profile.ObjectStoreType, _, _ = unstructured.NestedString(item.Object, "spec", "locationSpec", "objectStore", "objectStoreType")
+			profile.Bucket, _, _ = unstructured.NestedString(item.Object, "spec", "locationSpec", "objectStore", "name")
+			profile.Region, _, _ = unstructured.NestedString(item.Object, "spec", "locationSpec", "objectStore", "region")
+			profile.Validation, _, _ = unstructured.NestedString(item.Object, "status", "validation")
+			kasten.Profiles = append(kasten.Profiles, profile)
+		}),
+	}
+
+	// RestorePoints are served by the aggregated apps API, which can be
+	// missing while K10 is starting.
+	served, err := groupVersionServed(discoveryClient, "apps.kio.kasten.io/v1alpha1")
+	if err != nil || !served {
+		return kasten, errors.Join(append(errs, err)...)
+	}
+	restorePoints := schema.GroupVersionResource{Group: "apps.kio.kasten.io", Version: "v1alpha1", Resource: "restorepoints"}
+	errs = append(errs, opts.eachUnstructured(ctx, dynamicClient, restorePoints, func(item *unstructured.Unstructured) {
+		kasten.RestorePoints = append(kasten.RestorePoints, k8sdata.KastenRestorePointInfo{
+			Name:              item.GetName(),
+			Namespace:         item.GetNamespace(),
+			Policy:            item.GetLabels()["k10.kasten.io/policyName"],
+			CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+		})
+	}))
+	return kasten, errors.Join(errs...)
+}
+
+// kastenNamespaces resolves a policy's selector to namespace patterns. A
+// policy without a selector covers every namespace; one that selects on
+// anything but the application namespace label cannot be resolved and
+// covers none as far as kollect can tell.
+func kastenNamespaces(obj map[string]interface{}) (included, excluded []string) {
+	selector, _, _ := unstructured.NestedMap(obj, "spec", "selector")
+	if len(selector) == 0 {
+		return []string{"*"}, nil
+	}
+	matchLabels, _, _ := unstructured.NestedStringMap(selector, "matchLabels")
+	for key, value := range matchLabels {
+		if key != kastenNamespaceLabel {
+			return nil, nil
+		}
+		included = append(included, value)
+	}
+	expressions, _, _ := unstructured.NestedSlice(selector, "matchExpressions")
+	for _, expression := range expressions {
+		e, ok := expression.(map[string]interface{})
+		if !ok || e["key"] != kastenNamespaceLabel {
+			return nil, nil
+		}
+		values, _, _ := unstructured.NestedStringSlice(e, "values")
+		switch e["operator"] {
+		case "In":
+			included = append(included, values...)
+		case "NotIn":
+			excluded = append(excluded, values...)
+		default:
+			return nil, nil
+		}
+	}
+	if len(included) == 0 {
+		included = []string{"*"}
+	}
+	return included, excluded
+}
+
+// assessProtection works out, for every collected namespace, which Velero
+// Schedules and Kasten Policies back it up and when it was last backed up
+// successfully. Namespaces come from the Namespaces list, or from the
+// collected claims in storage-only runs.
+func assessProtection(data *k8sdata.K8sData) []k8sdata.NamespaceProtection {
+	if data.Velero == nil && data.Kasten == nil {
+		return nil
+	}
+	namespaces := data.Namespaces
+	if len(namespaces) == 0 {
+		seen := map[string]bool{}
+		for _, pvc := range data.PersistentVolumeClaims {
+			if !seen[pvc.Namespace] {
+				seen[pvc.Namespace] = true
+				namespaces = append(namespaces, pvc.Namespace)
+			}
+		}
+		sort.Strings(namespaces)
+	}
+
+	var protection []k8sdata.NamespaceProtection
+	for _, namespace := range namespaces {
+		status := k8sdata.NamespaceProtection{Namespace: namespace}
+		var latest time.Time
+		recordBackup := func(tool, name, timestamp string) {
+			t, err := time.Parse(time.RFC3339, timestamp)
+			if err != nil || !t.After(latest) {
+				return
+			}
+			latest = t
+			status.LastSuccessfulBackup = t.UTC().Format(time.RFC3339)
+			status.LastBackupTool = tool
+			status.LastBackupName = name
+		}
+		addSchedule := func(schedule k8sdata.ProtectionSchedule) {
+			status.Schedules = append(status.Schedules, schedule)
+			if !schedule.Paused {
+				status.Protected = true
+			}
+		}
+
+		if data.Velero != nil {
+			for _, backup := range data.Velero.Backups {
+				if backup.Phase == "Completed" && namespaceSelected(namespace, backup.IncludedNamespaces, backup.ExcludedNamespaces) {
+					recordBackup(k8sdata.ToolVelero, backup.Name, backup.CompletionTimestamp)
+				}
+			}
+			for _, schedule := range data.Velero.Schedules {
+				if namespaceSelected(namespace, schedule.IncludedNamespaces, schedule.ExcludedNamespaces) {
+					addSchedule(k8sdata.ProtectionSchedule{
+						Tool:      k8sdata.ToolVelero,
+						Namespace: schedule.Namespace,
+						Name:      schedule.Name,
+						Schedule:  schedule.Schedule,
+						Retention: schedule.TTL,
+						Paused:    schedule.Paused,
+					})
+				}
+			}
+		}
+		if data.Kasten != nil {
+			for _, restorePoint := range data.Kasten.RestorePoints {
+				if restorePoint.Namespace == namespace {
+					recordBackup(k8sdata.ToolKasten, restorePoint.Name, restorePoint.CreationTimestamp)
+				}
+			}
+			for _, policy := range data.Kasten.Policies {
+				if hasAction(policy.Actions, "backup") && namespaceSelected(namespace, policy.IncludedNamespaces, policy.ExcludedNamespaces) {
+					addSchedule(k8sdata.ProtectionSchedule{
+						Tool:      k8sdata.ToolKasten,
+						Namespace: policy.Namespace,
+						Name:      policy.Name,
+						Schedule:  policy.Frequency,
+						Retention: formatRetention(policy.Retention),
+						Paused:    policy.Paused,
+					})
+				}
+			}
+		}
+		protection = append(protection, status)
+	}
+	return protection
+}
+
+// namespaceSelected matches namespace against include and exclude patterns;
+// exclusions win.
+func namespaceSelected(namespace string, included, excluded []string) bool {
+	for _, pattern := range excluded {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return false
+		}
+	}
+	for _, pattern := range included {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRetention renders K10 retention counts as e.g. "daily=7 weekly=4".
+func formatRetention(retention map[string]int64) string {
+	var parts []string
+	for _, period := range kastenRetentionPeriods {
+		if count, ok := retention[period]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%d", period, count))
+		}
+	}
+	return strings.Join(parts, " ")
+}
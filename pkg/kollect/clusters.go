@@ -0,0 +1,71 @@
+package kollect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ContextNames returns the sorted names of every context in kubeconfig.
+func ContextNames(kubeconfig string) ([]string, error) {
+	rawConfig, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CollectClusters collects every named kubeconfig context in parallel. A
+// cluster that cannot be reached is still returned, with the failure in its
+// Errors; an error is only returned if no cluster could be collected.
+func CollectClusters(ctx context.Context, kubeconfig string, contexts []string, storageOnly bool, opts Options) (k8sdata.MultiClusterData, error) {
+	result := k8sdata.MultiClusterData{Clusters: make(map[string]k8sdata.K8sData, len(contexts))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := 0
+	for _, name := range contexts {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			data, err := collectContext(ctx, kubeconfig, name, storageOnly, opts)
+			data.Cluster.Context = name
+			if err != nil && len(data.Errors) == 0 {
+				data.Errors = append(data.Errors, k8sdata.CollectionError{
+					Resource: "Cluster",
+					Reason:   err.Error(),
+				})
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+			}
+			result.Clusters[name] = data
+		}(name)
+	}
+	wg.Wait()
+	if len(contexts) > 0 && failed == len(contexts) {
+		return result, fmt.Errorf("could not collect any of %d kubeconfig contexts", len(contexts))
+	}
+	return result, nil
+}
+
+func collectContext(ctx context.Context, kubeconfig, contextName string, storageOnly bool, opts Options) (k8sdata.K8sData, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+	if err != nil {
+		return k8sdata.K8sData{}, err
+	}
+	return collect(ctx, config, storageOnly, opts)
+}
@@ -0,0 +1,119 @@
+package kollect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	"github.com/michaelcade/kollect/pkg/collector"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func init() {
+	collector.Register("kubernetes", NewCollector)
+}
+
+var collectorSchema = []collector.Field{
+	{Name: "kubeconfig", Description: "Path to the kubeconfig file", Env: "KUBECONFIG", Default: filepath.Join(os.Getenv("HOME"), ".kube", "config")},
+	{Name: "storage", Description: "Collect only storage-related objects"},
+	{Name: "context", Description: "Comma separated kubeconfig contexts to collect"},
+	{Name: "all-contexts", Description: "Collect every context in the kubeconfig"},
+	{Name: "namespace", Description: "Comma separated namespaces to collect (default all)"},
+	{Name: "exclude-namespace", Description: "Comma separated namespaces to skip"},
+	{Name: "selector", Description: "Label selector applied to namespaced resources"},
+	{Name: "crd-groups", Description: "Comma separated API groups whose custom resources are collected, each with its subgroups (kio.kasten.io matches config.kio.kasten.io)", Default: strings.Join(DefaultCRDGroups, ",")},
+	{Name: "k8s-page-size", Description: "Number of objects requested per List call (0 disables paging)", Default: strconv.Itoa(DefaultPageSize)},
+	{Name: "k8s-timeout", Description: "Timeout for each List call", Default: DefaultTimeout.String()},
+	{Name: "snapshot-max-age", Description: "Age after which a claim's newest snapshot is reported as stale", Default: DefaultSnapshotMaxAge.String()},
+	{Name: "k8s-workers", Description: "Number of resource kinds fetched concurrently", Default: strconv.Itoa(DefaultWorkers)},
+}
+
+// Collector collects Kubernetes inventory through the collector registry.
+type Collector struct {
+	kubeconfig  string
+	storageOnly bool
+	contexts    []string
+	allContexts bool
+	opts        Options
+}
+
+// NewCollector returns a Kubernetes collector configured from cfg.
+func NewCollector(cfg collector.Config) collector.Collector {
+	cfg = cfg.Resolve(collectorSchema)
+	opts := DefaultOptions()
+	if n, err := strconv.ParseInt(cfg["k8s-page-size"], 10, 64); err == nil && n >= 0 {
+		opts.PageSize = n
+	}
+	if d, err := time.ParseDuration(cfg["k8s-timeout"]); err == nil && d >= 0 {
+		opts.Timeout = d
+	}
+	if n, err := strconv.Atoi(cfg["k8s-workers"]); err == nil && n > 0 {
+		opts.Workers = n
+	}
+	if d, err := time.ParseDuration(cfg["snapshot-max-age"]); err == nil && d > 0 {
+		opts.SnapshotMaxAge = d
+	}
+	opts.Namespaces = cfg.List("namespace")
+	opts.ExcludeNamespaces = cfg.List("exclude-namespace")
+	opts.LabelSelector = cfg["selector"]
+	opts.CRDGroups = cfg.List("crd-groups")
+	return &Collector{
+		kubeconfig:  cfg["kubeconfig"],
+		storageOnly: cfg.Bool("storage"),
+		contexts:    cfg.List("context"),
+		allContexts: cfg.Bool("all-contexts"),
+		opts:        opts,
+	}
+}
+
+func (c *Collector) Name() string {
+	return "kubernetes"
+}
+
+func (c *Collector) Schema() []collector.Field {
+	return collectorSchema
+}
+
+func (c *Collector) InventoryTypes() []collector.Inventory {
+	return []collector.Inventory{k8sdata.K8sData{}, k8sdata.MultiClusterData{}}
+}
+
+// Collect returns a k8sdata.K8sData for the current context, or a
+// k8sdata.MultiClusterData when contexts were selected.
+func (c *Collector) Collect(ctx context.Context) (collector.Inventory, error) {
+	contexts := c.contexts
+	if c.allContexts {
+		var err error
+		contexts, err = ContextNames(c.kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(contexts) > 0 {
+		return CollectClusters(ctx, c.kubeconfig, contexts, c.storageOnly, c.opts)
+	}
+	if c.storageOnly {
+		return CollectStorageData(ctx, c.kubeconfig, c.opts)
+	}
+	return CollectData(ctx, c.kubeconfig, c.opts)
+}
+
+// Check asks the API server for its version, which needs no RBAC beyond
+// authentication.
+func (c *Collector) Check(ctx context.Context) error {
+	config, err := clientcmd.BuildConfigFromFlags("", c.kubeconfig)
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	_, err = clientset.Discovery().ServerVersion()
+	return err
+}
@@ -0,0 +1,182 @@
+package kollect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultCRDGroups are the API groups whose custom resources are collected
+// when no allow-list is configured.
+var DefaultCRDGroups = []string{"velero.io", "kio.kasten.io", "cert-manager.io"}
+
+func fetchCustomResourceDefinitions(ctx context.Context, dynamicClient dynamic.Interface, opts Options) ([]k8sdata.CustomResourceDefinitionInfo, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+	var crdInfos []k8sdata.CustomResourceDefinitionInfo
+	err := opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return dynamicClient.Resource(gvr).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		crd := obj.(*unstructured.Unstructured)
+		crdInfo := k8sdata.CustomResourceDefinitionInfo{
+			Name:              crd.GetName(),
+			CreationTimestamp: creationTimestamp(crd.GetCreationTimestamp()),
+		}
+		crdInfo.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+		crdInfo.Kind, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		crdInfo.Scope, _, _ = unstructured.NestedString(crd.Object, "spec", "scope")
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, version := range versions {
+			if v, ok := version.(map[string]interface{}); ok {
+				if name, ok := v["name"].(string); ok {
+					crdInfo.Versions = append(crdInfo.Versions, name)
+				}
+			}
+		}
+		crdInfos = append(crdInfos, crdInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return crdInfos, nil
+}
+
+// groupAllowed reports whether an API group is one of allowed or a subgroup
+// of one, so that "kio.kasten.io" also matches "config.kio.kasten.io".
+func groupAllowed(group string, allowed []string) bool {
+	for _, entry := range allowed {
+		if group == entry || strings.HasSuffix(group, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchCustomResources uses discovery to find the preferred version of each
+// allow-listed group and lists every resource it serves. A resource that
+// cannot be listed does not stop the others; all failures are returned
+// together alongside what was collected.
+func fetchCustomResources(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, opts Options) ([]k8sdata.CustomResourceInfo, error) {
+	if len(opts.CRDGroups) == 0 {
+		return nil, nil
+	}
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceInfos []k8sdata.CustomResourceInfo
+	var errs []error
+	for _, group := range groups.Groups {
+		if !groupAllowed(group.Name, opts.CRDGroups) {
+			continue
+		}
+		groupVersion := group.PreferredVersion.GroupVersion
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", groupVersion, err))
+			continue
+		}
+		for _, resource := range resources.APIResources {
+			// Skip subresources such as "backups/status".
+			if strings.Contains(resource.Name, "/") || !hasVerb(resource.Verbs, "list") {
+				continue
+			}
+			gvr := schema.GroupVersionResource{Group: group.Name, Version: group.PreferredVersion.Version, Resource: resource.Name}
+			resourceInfo, err := fetchCustomResource(ctx, dynamicClient, gvr, resource, opts)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", gvr.GroupResource(), err))
+				continue
+			}
+			resourceInfos = append(resourceInfos, resourceInfo)
+		}
+	}
+	sort.Slice(resourceInfos, func(i, j int) bool {
+		if resourceInfos[i].Group != resourceInfos[j].Group {
+			return resourceInfos[i].Group < resourceInfos[j].Group
+		}
+		return resourceInfos[i].Resource < resourceInfos[j].Resource
+	})
+
+	return resourceInfos, errors.Join(errs...)
+}
+
+func fetchCustomResource(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, resource v1.APIResource, opts Options) (k8sdata.CustomResourceInfo, error) {
+	resourceInfo := k8sdata.CustomResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Kind:     resource.Kind,
+		Resource: gvr.Resource,
+	}
+	add := func(obj runtime.Object) error {
+		item := obj.(*unstructured.Unstructured)
+		resourceInfo.Instances = append(resourceInfo.Instances, k8sdata.CustomResourceInstance{
+			Name:              item.GetName(),
+			Namespace:         item.GetNamespace(),
+			CreationTimestamp: creationTimestamp(item.GetCreationTimestamp()),
+			Status:            customResourceStatus(item),
+		})
+		return nil
+	}
+	var err error
+	if resource.Namespaced {
+		err = opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+			return dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, lo)
+		}, add)
+	} else {
+		err = opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+			return dynamicClient.Resource(gvr).List(ctx, lo)
+		}, add)
+	}
+	resourceInfo.Count = len(resourceInfo.Instances)
+	return resourceInfo, err
+}
+
+// customResourceStatus picks a one-word status from the conventions most
+// operators follow.
+func customResourceStatus(item *unstructured.Unstructured) string {
+	for _, field := range []string{"phase", "state"} {
+		if status, found, err := unstructured.NestedString(item.Object, "status", field); err == nil && found && status != "" {
+			return status
+		}
+	}
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if !ok || c["type"] != "Ready" {
+			continue
+		}
+		if c["status"] == "True" {
+			return "Ready"
+		}
+		if reason, ok := c["reason"].(string); ok && reason != "" {
+			return reason
+		}
+		return "NotReady"
+	}
+	return ""
+}
+
+func hasVerb(verbs v1.Verbs, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package kollect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestGroupAllowed(t *testing.T) {
+	allowed := []string{"velero.io", "kio.kasten.io"}
+	tests := []struct {
+		group string
+		want  bool
+	}{
+		{"velero.io", true},
+		{"kio.kasten.io", true},
+		{"config.kio.kasten.io", true},
+		{"apps.kio.kasten.io", true},
+		{"notkio.kasten.io", false},
+		{"kasten.io", false},
+		{"example.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := groupAllowed(tt.group, allowed); got != tt.want {
+			t.Errorf("groupAllowed(%q) = %v, want %v", tt.group, got, tt.want)
+		}
+	}
+}
+
+func customResource(apiVersion, kind, namespace, name, phase string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	if phase != "" {
+		_ = unstructured.SetNestedField(obj.Object, phase, "status", "phase")
+	}
+	return obj
+}
+
+func TestFetchCustomResources(t *testing.T) {
+	list := []string{"get", "list", "watch"}
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*v1.APIResourceList{
+		{GroupVersion: "velero.io/v1", APIResources: []v1.APIResource{
+			{Name: "backups", Kind: "Backup", Namespaced: true, Verbs: list},
+			{Name: "backups/status", Kind: "Backup", Namespaced: true, Verbs: list},
+			{Name: "deletebackuprequests", Kind: "DeleteBackupRequest", Namespaced: true, Verbs: []string{"create"}},
+		}},
+		{GroupVersion: "config.kio.kasten.io/v1alpha1", APIResources: []v1.APIResource{
+			{Name: "policies", Kind: "Policy", Namespaced: true, Verbs: list},
+			{Name: "profiles", Kind: "Profile", Namespaced: true, Verbs: list},
+		}},
+		{GroupVersion: "notkio.kasten.io/v1", APIResources: []v1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: list},
+		}},
+		{GroupVersion: "example.com/v1", APIResources: []v1.APIResource{
+			{Name: "clusterwidgets", Kind: "ClusterWidget", Verbs: list},
+		}},
+	}}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "velero.io", Version: "v1", Resource: "backups"}:                   "BackupList",
+			{Group: "config.kio.kasten.io", Version: "v1alpha1", Resource: "policies"}: "PolicyList",
+			{Group: "config.kio.kasten.io", Version: "v1alpha1", Resource: "profiles"}: "ProfileList",
+			{Group: "notkio.kasten.io", Version: "v1", Resource: "widgets"}:            "WidgetList",
+			{Group: "example.com", Version: "v1", Resource: "clusterwidgets"}:          "ClusterWidgetList",
+		},
+		customResource("velero.io/v1", "Backup", "velero", "nightly", "Completed"),
+		customResource("config.kio.kasten.io/v1alpha1", "Policy", "kasten-io", "daily", ""),
+		customResource("config.kio.kasten.io/v1alpha1", "Policy", "kasten-io", "weekly", ""),
+		customResource("notkio.kasten.io/v1", "Widget", "default", "w", ""),
+	)
+	dynamicClient.PrependReactor("list", "profiles", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("profiles is forbidden")
+	})
+
+	opts := DefaultOptions()
+	opts.CRDGroups = []string{"velero.io", "kio.kasten.io"}
+	got, err := fetchCustomResources(context.Background(), discoveryClient, dynamicClient, opts)
+	if err == nil || !strings.Contains(err.Error(), "profiles.config.kio.kasten.io: profiles is forbidden") {
+		t.Errorf("error = %v, want the profiles list failure", err)
+	}
+
+	want := []k8sdata.CustomResourceInfo{
+		{Group: "config.kio.kasten.io", Version: "v1alpha1", Kind: "Policy", Resource: "policies", Count: 2, Instances: []k8sdata.CustomResourceInstance{
+			{Name: "daily", Namespace: "kasten-io"},
+			{Name: "weekly", Namespace: "kasten-io"},
+		}},
+		{Group: "velero.io", Version: "v1", Kind: "Backup", Resource: "backups", Count: 1, Instances: []k8sdata.CustomResourceInstance{
+			{Name: "nightly", Namespace: "velero", Status: "Completed"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fetchCustomResources =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package kollect
+
+import (
+	"sort"
+	"strings"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// buildGraph links the collected workloads to the storage they use and
+// fills data.Edges and data.StorageFootprints. It works from what was
+// collected, so resources that could not be listed simply leave gaps.
+func buildGraph(data *k8sdata.K8sData) {
+	var edges []k8sdata.Edge
+	addEdge := func(from, to k8sdata.ObjectRef, relation string) {
+		edges = append(edges, k8sdata.Edge{From: from, To: to, Relation: relation})
+	}
+
+	claims := make(map[string]k8sdata.PersistentVolumeClaimInfo, len(data.PersistentVolumeClaims))
+	for _, pvc := range data.PersistentVolumeClaims {
+		claims[pvc.Namespace+"/"+pvc.Name] = pvc
+	}
+	volumes := make(map[string]k8sdata.PersistentVolumeInfo, len(data.PersistentVolumes))
+	// bound maps claims to their volumes from the volumes' claimRefs. A
+	// claim's volumeName can be set before the volume accepts it, and a
+	// released volume still names the claim it was bound to, so only
+	// volumes in the Bound phase count.
+	bound := map[string]string{}
+	for _, pv := range data.PersistentVolumes {
+		volumes[pv.Name] = pv
+		if pv.AssociatedClaim != "" && pv.Status == string(corev1.VolumeBound) {
+			bound[pv.ClaimNamespace+"/"+pv.AssociatedClaim] = pv.Name
+		}
+	}
+	provisioners := make(map[string]string, len(data.StorageClasses))
+	for _, sc := range data.StorageClasses {
+		provisioners[sc.Name] = sc.Provisioner
+	}
+	snapshotClasses := map[string][]string{}
+	for _, vsc := range data.VolumeSnapshotClasses {
+		snapshotClasses[vsc.Driver] = append(snapshotClasses[vsc.Driver], vsc.Name)
+	}
+
+	// Workloads own pods, pods mount claims.
+	owners := ownerIndex(data)
+	footprints := map[k8sdata.ObjectRef][]string{}
+	for _, pod := range data.Pods {
+		podRef := k8sdata.ObjectRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+		workload := podRef
+		if pod.OwnerKind != "" {
+			workload = resolveWorkload(data, owners, pod.Namespace, pod.OwnerKind, pod.OwnerName)
+			addEdge(workload, podRef, k8sdata.RelationOwns)
+		}
+		for _, claim := range pod.PersistentVolumeClaims {
+			addEdge(podRef, k8sdata.ObjectRef{Kind: "PersistentVolumeClaim", Namespace: pod.Namespace, Name: claim}, k8sdata.RelationMounts)
+			footprints[workload] = append(footprints[workload], pod.Namespace+"/"+claim)
+		}
+	}
+
+	// Claims bind to volumes, volumes use a class, classes are provisioned
+	// by a driver, drivers can be snapshotted by snapshot classes.
+	for _, pv := range data.PersistentVolumes {
+		pvRef := k8sdata.ObjectRef{Kind: "PersistentVolume", Name: pv.Name}
+		if bound[pv.ClaimNamespace+"/"+pv.AssociatedClaim] == pv.Name {
+			addEdge(k8sdata.ObjectRef{Kind: "PersistentVolumeClaim", Namespace: pv.ClaimNamespace, Name: pv.AssociatedClaim},
+				pvRef, k8sdata.RelationBoundTo)
+		}
+		if pv.StorageClass != "" {
+			addEdge(pvRef, k8sdata.ObjectRef{Kind: "StorageClass", Name: pv.StorageClass}, k8sdata.RelationUsesClass)
+		}
+		if pv.Driver != "" && pv.Driver != provisioners[pv.StorageClass] {
+			addEdge(pvRef, driverRef(pv.Driver), k8sdata.RelationProvisionedBy)
+		}
+	}
+	for _, sc := range data.StorageClasses {
+		addEdge(k8sdata.ObjectRef{Kind: "StorageClass", Name: sc.Name}, driverRef(sc.Provisioner), k8sdata.RelationProvisionedBy)
+	}
+	for _, vsc := range data.VolumeSnapshotClasses {
+		addEdge(driverRef(vsc.Driver), k8sdata.ObjectRef{Kind: "VolumeSnapshotClass", Name: vsc.Name}, k8sdata.RelationSnapshottedBy)
+	}
+
+	data.Edges = dedupeEdges(edges)
+
+	data.StorageFootprints = nil
+	for workload, keys := range footprints {
+		footprint := k8sdata.StorageFootprint{Workload: workload}
+		seen := map[string]bool{}
+		for _, key := range keys {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			volume := k8sdata.FootprintVolume{Claim: key[strings.Index(key, "/")+1:]}
+			volume.Volume = bound[key]
+			if pvc, ok := claims[key]; ok {
+				volume.Capacity = pvc.Capacity
+				volume.StorageClass = pvc.StorageClass
+			}
+			if pv, ok := volumes[volume.Volume]; ok {
+				volume.Driver = pv.Driver
+				if volume.StorageClass == "" {
+					volume.StorageClass = pv.StorageClass
+				}
+			}
+			if volume.Driver == "" {
+				volume.Driver = provisioners[volume.StorageClass]
+			}
+			volume.SnapshotClasses = snapshotClasses[volume.Driver]
+			footprint.Volumes = append(footprint.Volumes, volume)
+		}
+		sort.Slice(footprint.Volumes, func(i, j int) bool {
+			return footprint.Volumes[i].Claim < footprint.Volumes[j].Claim
+		})
+		data.StorageFootprints = append(data.StorageFootprints, footprint)
+	}
+	sort.Slice(data.StorageFootprints, func(i, j int) bool {
+		return refLess(data.StorageFootprints[i].Workload, data.StorageFootprints[j].Workload)
+	})
+}
+
+// ownerIndex maps collected ReplicaSets and Jobs to their controllers.
+func ownerIndex(data *k8sdata.K8sData) map[k8sdata.ObjectRef]k8sdata.ObjectRef {
+	owners := make(map[k8sdata.ObjectRef]k8sdata.ObjectRef, len(data.ReplicaSets)+len(data.Jobs))
+	for _, replicaSet := range data.ReplicaSets {
+		if replicaSet.OwnerKind != "" {
+			owners[k8sdata.ObjectRef{Kind: "ReplicaSet", Namespace: replicaSet.Namespace, Name: replicaSet.Name}] =
+				k8sdata.ObjectRef{Kind: replicaSet.OwnerKind, Namespace: replicaSet.Namespace, Name: replicaSet.OwnerName}
+		}
+	}
+	for _, job := range data.Jobs {
+		if job.OwnerKind != "" {
+			owners[k8sdata.ObjectRef{Kind: "Job", Namespace: job.Namespace, Name: job.Name}] =
+				k8sdata.ObjectRef{Kind: job.OwnerKind, Namespace: job.Namespace, Name: job.OwnerName}
+		}
+	}
+	return owners
+}
+
+// resolveWorkload maps a pod's controller to the workload users manage,
+// following ReplicaSets up to their Deployment and Jobs up to their
+// CronJob. When ReplicaSets were not collected, a ReplicaSet named
+// "<deployment>-<pod-template-hash>" is matched to a collected Deployment
+// by name.
+func resolveWorkload(data *k8sdata.K8sData, owners map[k8sdata.ObjectRef]k8sdata.ObjectRef, namespace, kind, name string) k8sdata.ObjectRef {
+	ref := k8sdata.ObjectRef{Kind: kind, Namespace: namespace, Name: name}
+	if owner, ok := owners[ref]; ok {
+		return owner
+	}
+	if kind == "ReplicaSet" && len(data.ReplicaSets) == 0 {
+		for _, deployment := range data.Deployments {
+			if deployment.Namespace != namespace || !strings.HasPrefix(name, deployment.Name+"-") {
+				continue
+			}
+			if hash := strings.TrimPrefix(name, deployment.Name+"-"); !strings.Contains(hash, "-") {
+				return k8sdata.ObjectRef{Kind: "Deployment", Namespace: namespace, Name: deployment.Name}
+			}
+		}
+	}
+	return ref
+}
+
+// driverRef names the node for a provisioner: a CSIDriver, or an
+// InTreeProvisioner for the legacy kubernetes.io/ plugins.
+func driverRef(provisioner string) k8sdata.ObjectRef {
+	if isInTree(provisioner) {
+		return k8sdata.ObjectRef{Kind: "InTreeProvisioner", Name: provisioner}
+	}
+	return k8sdata.ObjectRef{Kind: "CSIDriver", Name: provisioner}
+}
+
+func dedupeEdges(edges []k8sdata.Edge) []k8sdata.Edge {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return refLess(edges[i].From, edges[j].From)
+		}
+		if edges[i].To != edges[j].To {
+			return refLess(edges[i].To, edges[j].To)
+		}
+		return edges[i].Relation < edges[j].Relation
+	})
+	var out []k8sdata.Edge
+	for i, edge := range edges {
+		if i > 0 && edge == edges[i-1] {
+			continue
+		}
+		out = append(out, edge)
+	}
+	return out
+}
+
+func refLess(a, b k8sdata.ObjectRef) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,47 @@
+package kollect
+
+import (
+	"testing"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+)
+
+func TestBuildGraphBindsClaimsFromVolumes(t *testing.T) {
+	data := &k8sdata.K8sData{
+		Pods: []k8sdata.PodsInfo{{Name: "db-0", Namespace: "default", PersistentVolumeClaims: []string{"data", "pending"}}},
+		PersistentVolumeClaims: []k8sdata.PersistentVolumeClaimInfo{
+			{Name: "data", Namespace: "default", Status: "Bound", Volume: "pv-data", Capacity: "10Gi"},
+			// volumeName set ahead of binding.
+			{Name: "pending", Namespace: "default", Status: "Pending", Volume: "pv-pending"},
+		},
+		PersistentVolumes: []k8sdata.PersistentVolumeInfo{
+			{Name: "pv-data", Status: "Bound", AssociatedClaim: "data", ClaimNamespace: "default"},
+			{Name: "pv-pending", Status: "Available"},
+			{Name: "pv-old", Status: "Released", AssociatedClaim: "old", ClaimNamespace: "default"},
+		},
+	}
+	buildGraph(data)
+
+	var boundTo []k8sdata.Edge
+	for _, edge := range data.Edges {
+		if edge.Relation == k8sdata.RelationBoundTo {
+			boundTo = append(boundTo, edge)
+		}
+	}
+	want := k8sdata.Edge{
+		From:     k8sdata.ObjectRef{Kind: "PersistentVolumeClaim", Namespace: "default", Name: "data"},
+		To:       k8sdata.ObjectRef{Kind: "PersistentVolume", Name: "pv-data"},
+		Relation: k8sdata.RelationBoundTo,
+	}
+	if len(boundTo) != 1 || boundTo[0] != want {
+		t.Errorf("boundTo edges = %+v, want only %+v", boundTo, want)
+	}
+
+	if len(data.StorageFootprints) != 1 {
+		t.Fatalf("got %d footprints, want 1", len(data.StorageFootprints))
+	}
+	volumes := data.StorageFootprints[0].Volumes
+	if len(volumes) != 2 || volumes[0].Claim != "data" || volumes[0].Volume != "pv-data" || volumes[1].Volume != "" {
+		t.Errorf("footprint volumes = %+v", volumes)
+	}
+}
@@ -4,62 +4,53 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"log"
 
 	k8sdata "github.com/michaelcade/kollect/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-func CollectStorageData(ctx context.Context, kubeconfig string) (k8sdata.K8sData, error) {
-	var data k8sdata.K8sData
+// fetchTask lists one kind of resource into the K8sData being built.
+type fetchTask struct {
+	resource string
+	fetch    func(ctx context.Context) error
+}
+
+func CollectStorageData(ctx context.Context, kubeconfig string, opts Options) (k8sdata.K8sData, error) {
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
 		return k8sdata.K8sData{}, err
 	}
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return k8sdata.K8sData{}, err
-	}
-	dynamicClient, err := dynamic.NewForConfig(config)
-	if err != nil {
-		return k8sdata.K8sData{}, err
-	}
-	data.PersistentVolumes, err = fetchPersistentVolumes(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching PersistentVolumes: %v", err)
-	}
-	data.PersistentVolumeClaims, err = fetchPersistentVolumeClaims(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching PersistentVolumeClaims: %v", err)
-	}
-	data.StorageClasses, err = fetchStorageClasses(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching StorageClasses: %v", err)
-	}
-	data.VolumeSnapshotClasses, err = fetchVolumeSnapshotClasses(ctx, dynamicClient)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching VolumeSnapshotClasses: %v", err)
-	}
-	data.VolumeSnapshots, err = fetchVolumeSnapshots(ctx, dynamicClient)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching VolumeSnapshots: %v", err)
-	}
-	return data, nil
+	return collect(ctx, config, true, opts)
 }
 
-func CollectData(ctx context.Context, kubeconfig string) (k8sdata.K8sData, error) {
-	var data k8sdata.K8sData
+func CollectData(ctx context.Context, kubeconfig string, opts Options) (k8sdata.K8sData, error) {
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
 		return k8sdata.K8sData{}, err
 	}
+	return collect(ctx, config, false, opts)
+}
+
+// collect gathers inventory from the cluster config points at. Storage
+// objects are always collected; workloads only when storageOnly is false.
+func collect(ctx context.Context, config *rest.Config, storageOnly bool, opts Options) (k8sdata.K8sData, error) {
+	var data k8sdata.K8sData
+	data.Cluster.Server = config.Host
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return k8sdata.K8sData{}, err
@@ -68,334 +59,492 @@ func CollectData(ctx context.Context, kubeconfig string) (k8sdata.K8sData, error
 	if err != nil {
 		return k8sdata.K8sData{}, err
 	}
-	data.Nodes, err = fetchNodes(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching Nodes: %v", err)
-	}
-	data.Namespaces, err = fetchNamespaces(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching Namespaces: %v", err)
-	}
-	data.Pods, err = fetchPods(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching Pods: %v", err)
-	}
-	data.Deployments, err = fetchDeployments(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching Deployments: %v", err)
-	}
-	data.StatefulSets, err = fetchStatefulSets(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching StatefulSets: %v", err)
+	tasks := []fetchTask{
+		{"ServerVersion", func(ctx context.Context) error {
+			version, err := clientset.Discovery().ServerVersion()
+			if err != nil {
+				return err
+			}
+			data.Cluster.Version = version.GitVersion
+			return nil
+		}},
+	}
+	// Node pod counts come from the Pods inventory unless scoping leaves
+	// pods out of it, in which case they need a list of their own. If that
+	// source fails the counts stay nil, i.e. unknown rather than zero.
+	var podCounts map[string]int
+	if !storageOnly {
+		tasks = append(tasks, workloadTasks(&data, clientset, opts)...)
+		if opts.scoped() {
+			tasks = append(tasks, fetchTask{"NodePodCounts", func(ctx context.Context) error {
+				counts, err := countPodsPerNode(ctx, clientset, opts)
+				if err != nil {
+					return err
+				}
+				podCounts = counts
+				return nil
+			}})
+		}
+		tasks = append(tasks, customResourceTasks(&data, clientset, dynamicClient, opts)...)
+	}
+	tasks = append(tasks, storageTasks(&data, clientset, dynamicClient, opts)...)
+	tasks = append(tasks, backupTasks(&data, clientset.Discovery(), dynamicClient, opts)...)
+	err = runFetchTasks(ctx, &data, opts.Workers, tasks)
+	if !storageOnly && !opts.scoped() && !fetchFailed(data.Errors, "Pods") {
+		podCounts = podsPerNode(data.Pods)
+	}
+	setNodePodCounts(data.Nodes, podCounts)
+	buildGraph(&data)
+	data.ResourceSummary = summarizeResources(data.Nodes)
+	data.NamespaceProtection = assessProtection(&data)
+	data.Readiness = assessReadiness(&data, time.Now(), opts.SnapshotMaxAge)
+	return data, err
+}
+
+func workloadTasks(data *k8sdata.K8sData, clientset *kubernetes.Clientset, opts Options) []fetchTask {
+	return []fetchTask{
+		{"Nodes", func(ctx context.Context) (err error) {
+			data.Nodes, err = fetchNodes(ctx, clientset, opts)
+			return err
+		}},
+		{"Namespaces", func(ctx context.Context) (err error) {
+			data.Namespaces, err = fetchNamespaces(ctx, clientset, opts)
+			return err
+		}},
+		{"Pods", func(ctx context.Context) (err error) {
+			data.Pods, err = fetchPods(ctx, clientset, opts)
+			return err
+		}},
+		{"Deployments", func(ctx context.Context) (err error) {
+			data.Deployments, err = fetchDeployments(ctx, clientset, opts)
+			return err
+		}},
+		{"StatefulSets", func(ctx context.Context) (err error) {
+			data.StatefulSets, err = fetchStatefulSets(ctx, clientset, opts)
+			return err
+		}},
+		{"DaemonSets", func(ctx context.Context) (err error) {
+			data.DaemonSets, err = fetchDaemonSets(ctx, clientset, opts)
+			return err
+		}},
+		{"ReplicaSets", func(ctx context.Context) (err error) {
+			data.ReplicaSets, err = fetchReplicaSets(ctx, clientset, opts)
+			return err
+		}},
+		{"Jobs", func(ctx context.Context) (err error) {
+			data.Jobs, err = fetchJobs(ctx, clientset, opts)
+			return err
+		}},
+		{"CronJobs", func(ctx context.Context) (err error) {
+			data.CronJobs, err = fetchCronJobs(ctx, clientset, opts)
+			return err
+		}},
+		{"Services", func(ctx context.Context) (err error) {
+			data.Services, err = fetchServices(ctx, clientset, opts)
+			return err
+		}},
+		{"Ingresses", func(ctx context.Context) (err error) {
+			data.Ingresses, err = fetchIngresses(ctx, clientset, opts)
+			return err
+		}},
+		{"ConfigMaps", func(ctx context.Context) (err error) {
+			data.ConfigMaps, err = fetchConfigMaps(ctx, clientset, opts)
+			return err
+		}},
+		{"Secrets", func(ctx context.Context) (err error) {
+			data.Secrets, err = fetchSecrets(ctx, clientset, opts)
+			return err
+		}},
+		{"HorizontalPodAutoscalers", func(ctx context.Context) (err error) {
+			data.HorizontalPodAutoscalers, err = fetchHorizontalPodAutoscalers(ctx, clientset, opts)
+			return err
+		}},
+		{"PodDisruptionBudgets", func(ctx context.Context) (err error) {
+			data.PodDisruptionBudgets, err = fetchPodDisruptionBudgets(ctx, clientset, opts)
+			return err
+		}},
 	}
-	data.Services, err = fetchServices(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching Services: %v", err)
+}
+
+func customResourceTasks(data *k8sdata.K8sData, clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, opts Options) []fetchTask {
+	return []fetchTask{
+		{"CustomResourceDefinitions", func(ctx context.Context) (err error) {
+			data.CustomResourceDefinitions, err = fetchCustomResourceDefinitions(ctx, dynamicClient, opts)
+			return err
+		}},
+		{"CustomResources", func(ctx context.Context) (err error) {
+			data.CustomResources, err = fetchCustomResources(ctx, clientset.Discovery(), dynamicClient, opts)
+			return err
+		}},
 	}
-	data.PersistentVolumes, err = fetchPersistentVolumes(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching PersistentVolumes: %v", err)
+}
+
+func storageTasks(data *k8sdata.K8sData, clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, opts Options) []fetchTask {
+	return []fetchTask{
+		{"PersistentVolumes", func(ctx context.Context) (err error) {
+			data.PersistentVolumes, err = fetchPersistentVolumes(ctx, clientset, opts)
+			return err
+		}},
+		{"PersistentVolumeClaims", func(ctx context.Context) (err error) {
+			data.PersistentVolumeClaims, err = fetchPersistentVolumeClaims(ctx, clientset, opts)
+			return err
+		}},
+		{"StorageClasses", func(ctx context.Context) (err error) {
+			data.StorageClasses, err = fetchStorageClasses(ctx, clientset, opts)
+			return err
+		}},
+		{"VolumeSnapshotClasses", func(ctx context.Context) (err error) {
+			data.VolumeSnapshotClasses, err = fetchVolumeSnapshotClasses(ctx, dynamicClient, opts)
+			return err
+		}},
+		{"VolumeSnapshots", func(ctx context.Context) (err error) {
+			data.VolumeSnapshots, err = fetchVolumeSnapshots(ctx, dynamicClient, opts)
+			return err
+		}},
 	}
-	data.PersistentVolumeClaims, err = fetchPersistentVolumeClaims(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching PersistentVolumeClaims: %v", err)
+}
+
+// runFetchTasks attempts every task on a pool of workers, recording
+// failures in data.Errors rather than stopping at the first one. It only
+// returns an error when no task succeeded, which usually means the cluster
+// is unreachable.
+func runFetchTasks(ctx context.Context, data *k8sdata.K8sData, workers int, tasks []fetchTask) error {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, len(tasks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = tasks[i].fetch(ctx)
+			}
+		}()
 	}
-	data.StorageClasses, err = fetchStorageClasses(ctx, clientset)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching StorageClasses: %v", err)
+	for i := range tasks {
+		indexes <- i
 	}
-	data.VolumeSnapshotClasses, err = fetchVolumeSnapshotClasses(ctx, dynamicClient)
-	if err != nil {
-		return k8sdata.K8sData{}, fmt.Errorf("error fetching VolumeSnapshotClasses: %v", err)
+	close(indexes)
+	wg.Wait()
+
+	// Report errors in task order so output is stable between runs.
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		log.Printf("Warning: could not fetch %s: %v", tasks[i].resource, err)
+		data.Errors = append(data.Errors, k8sdata.CollectionError{
+			Resource:  tasks[i].resource,
+			Reason:    err.Error(),
+			Forbidden: apierrors.IsForbidden(err),
+		})
 	}
-	data.VolumeSnapshots, err = fetchVolumeSnapshots(ctx, dynamicClient)
-	if err != nil {
-		log.Printf("Warning: VolumeSnapshots resource not found in the cluster: %v", err)
-		data.VolumeSnapshots = []k8sdata.VolumeSnapshotInfo{}
+	if len(tasks) > 0 && len(data.Errors) == len(tasks) {
+		return fmt.Errorf("error fetching %s: %s", data.Errors[0].Resource, data.Errors[0].Reason)
 	}
-	return data, nil
+	return nil
 }
 
-func fetchNodes(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.NodeInfo, error) {
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-	var nodeInfos []k8sdata.NodeInfo
-	for _, node := range nodes.Items {
-		roles := "none"
-		for label := range node.Labels {
-			if strings.HasPrefix(label, "node-role.kubernetes.io/") {
-				role := strings.TrimPrefix(label, "node-role.kubernetes.io/")
-				if roles == "none" {
-					roles = role
-				} else {
-					roles += "," + role
-				}
-			}
+// fetchFailed reports whether the fetch task for resource recorded an error.
+func fetchFailed(errs []k8sdata.CollectionError, resource string) bool {
+	for _, e := range errs {
+		if e.Resource == resource {
+			return true
 		}
-		age := formatDuration(time.Since(node.CreationTimestamp.Time))
-		version := node.Status.NodeInfo.KubeletVersion
-		osImage := node.Status.NodeInfo.OSImage
-		nodeInfos = append(nodeInfos, k8sdata.NodeInfo{
-			Name:    node.Name,
-			Roles:   roles,
-			Age:     age,
-			Version: version,
-			OSImage: osImage,
-		})
 	}
-	return nodeInfos, nil
+	return false
 }
 
-func formatDuration(d time.Duration) string {
-	days := d / (24 * time.Hour)
-	d -= days * 24 * time.Hour
-	hours := d / time.Hour
-	d -= hours * time.Hour
-	minutes := d / time.Minute
-	return fmt.Sprintf("%dd%dh%dm", days, hours, minutes)
+// creationTimestamp formats an object's creation time as RFC3339 in UTC.
+// Ages are derived from it when the data is displayed.
+func creationTimestamp(t v1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
 }
 
-func fetchNamespaces(ctx context.Context, clientset *kubernetes.Clientset) ([]string, error) {
-	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
+func fetchNamespaces(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]string, error) {
+	// A scoped collection reports the namespaces it was asked for, so it
+	// does not need the right to list namespaces cluster-wide.
+	if namespaces := opts.scopedNamespaces(); namespaces != nil {
+		return namespaces, nil
 	}
 
-	var namespaceNames []string
-	for _, namespace := range namespaces.Items {
-		namespaceNames = append(namespaceNames, namespace.Name)
+	excluded := make(map[string]bool, len(opts.ExcludeNamespaces))
+	for _, ns := range opts.ExcludeNamespaces {
+		excluded[ns] = true
 	}
-
-	return namespaceNames, nil
-}
-
-func fetchPods(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.PodsInfo, error) {
-	pods, err := clientset.CoreV1().Pods("").List(ctx, v1.ListOptions{})
+	var namespaceNames []string
+	err := opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().Namespaces().List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		if name := obj.(*corev1.Namespace).Name; !excluded[name] {
+			namespaceNames = append(namespaceNames, name)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var podInfos []k8sdata.PodsInfo
-	for _, pod := range pods.Items {
-		podInfos = append(podInfos, k8sdata.PodsInfo{
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			Status:    string(pod.Status.Phase),
-		})
-	}
-
-	return podInfos, nil
+	return namespaceNames, nil
 }
 
-func fetchDeployments(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.DeploymentInfo, error) {
-	deployments, err := clientset.AppsV1().Deployments("").List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func fetchDeployments(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.DeploymentInfo, error) {
 	var deploymentInfos []k8sdata.DeploymentInfo
-	for _, deployment := range deployments.Items {
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.AppsV1().Deployments(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		deployment := obj.(*appsv1.Deployment)
 		var containers []string
 		var images []string
 		for _, container := range deployment.Spec.Template.Spec.Containers {
 			containers = append(containers, container.Name)
 			images = append(images, container.Image)
 		}
-		deploymentInfos = append(deploymentInfos, k8sdata.DeploymentInfo{
-			Name:       deployment.Name,
-			Namespace:  deployment.Namespace,
-			Containers: containers,
-			Images:     images,
-		})
+		deploymentInfo := k8sdata.DeploymentInfo{
+			Name:              deployment.Name,
+			Namespace:         deployment.Namespace,
+			CreationTimestamp: creationTimestamp(deployment.CreationTimestamp),
+			ReadyReplicas:     deployment.Status.ReadyReplicas,
+			Containers:        containers,
+			Images:            images,
+		}
+		if deployment.Spec.Replicas != nil {
+			deploymentInfo.Replicas = *deployment.Spec.Replicas
+		}
+		deploymentInfos = append(deploymentInfos, deploymentInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return deploymentInfos, nil
 }
 
-func fetchStatefulSets(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.StatefulSetInfo, error) {
-	statefulSets, err := clientset.AppsV1().StatefulSets("").List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func fetchStatefulSets(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.StatefulSetInfo, error) {
 	var statefulSetInfos []k8sdata.StatefulSetInfo
-	for _, statefulSet := range statefulSets.Items {
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.AppsV1().StatefulSets(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		statefulSet := obj.(*appsv1.StatefulSet)
 		image := ""
 		if len(statefulSet.Spec.Template.Spec.Containers) > 0 {
 			image = statefulSet.Spec.Template.Spec.Containers[0].Image
 		}
-		statefulSetInfos = append(statefulSetInfos, k8sdata.StatefulSetInfo{
-			Name:          statefulSet.Name,
-			Namespace:     statefulSet.Namespace,
-			ReadyReplicas: statefulSet.Status.ReadyReplicas,
-			Image:         image,
-		})
+		statefulSetInfo := k8sdata.StatefulSetInfo{
+			Name:              statefulSet.Name,
+			Namespace:         statefulSet.Namespace,
+			CreationTimestamp: creationTimestamp(statefulSet.CreationTimestamp),
+			ReadyReplicas:     statefulSet.Status.ReadyReplicas,
+			Image:             image,
+		}
+		if statefulSet.Spec.Replicas != nil {
+			statefulSetInfo.Replicas = *statefulSet.Spec.Replicas
+		}
+		statefulSetInfos = append(statefulSetInfos, statefulSetInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return statefulSetInfos, nil
 }
 
-func fetchServices(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.ServiceInfo, error) {
-	services, err := clientset.CoreV1().Services("").List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func fetchServices(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.ServiceInfo, error) {
 	var serviceInfos []k8sdata.ServiceInfo
-	for _, service := range services.Items {
-		ports := []string{}
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().Services(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		service := obj.(*corev1.Service)
+		var ports []k8sdata.ServicePort
 		for _, port := range service.Spec.Ports {
-			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+			ports = append(ports, k8sdata.ServicePort{
+				Name:       port.Name,
+				Protocol:   string(port.Protocol),
+				Port:       port.Port,
+				TargetPort: port.TargetPort.String(),
+				NodePort:   port.NodePort,
+			})
 		}
 		serviceInfos = append(serviceInfos, k8sdata.ServiceInfo{
-			Name:      service.Name,
-			Namespace: service.Namespace,
-			Type:      string(service.Spec.Type),
-			ClusterIP: service.Spec.ClusterIP,
-			Ports:     strings.Join(ports, ","),
+			Name:              service.Name,
+			Namespace:         service.Namespace,
+			CreationTimestamp: creationTimestamp(service.CreationTimestamp),
+			Type:              string(service.Spec.Type),
+			ClusterIP:         service.Spec.ClusterIP,
+			Ports:             ports,
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return serviceInfos, nil
 }
 
-func fetchPersistentVolumes(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.PersistentVolumeInfo, error) {
-	pvs, err := clientset.CoreV1().PersistentVolumes().List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func fetchPersistentVolumes(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.PersistentVolumeInfo, error) {
 	var pvInfos []k8sdata.PersistentVolumeInfo
-	for _, pv := range pvs.Items {
-		accessModes := []string{}
+	err := opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().PersistentVolumes().List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		pv := obj.(*corev1.PersistentVolume)
+		var accessModes []string
 		for _, mode := range pv.Spec.AccessModes {
 			accessModes = append(accessModes, string(mode))
 		}
-		accessModesStr := strings.Join(accessModes, ",")
+		associatedClaim, claimNamespace := "", ""
+		if pv.Spec.ClaimRef != nil {
+			associatedClaim = pv.Spec.ClaimRef.Name
+			claimNamespace = pv.Spec.ClaimRef.Namespace
+		}
+		driver := ""
+		if pv.Spec.CSI != nil {
+			driver = pv.Spec.CSI.Driver
+		}
+		volumeMode := ""
+		if pv.Spec.VolumeMode != nil {
+			volumeMode = string(*pv.Spec.VolumeMode)
+		}
 		pvInfos = append(pvInfos, k8sdata.PersistentVolumeInfo{
-			Name:            pv.Name,
-			Capacity:        pv.Spec.Capacity.Storage().String(),
-			AccessModes:     accessModesStr,
-			Status:          string(pv.Status.Phase),
-			AssociatedClaim: pv.Spec.ClaimRef.Name,
-			StorageClass:    pv.Spec.StorageClassName,
-			VolumeMode:      string(*pv.Spec.VolumeMode),
+			Name:              pv.Name,
+			CreationTimestamp: creationTimestamp(pv.CreationTimestamp),
+			Capacity:          pv.Spec.Capacity.Storage().String(),
+			AccessModes:       accessModes,
+			Status:            string(pv.Status.Phase),
+			AssociatedClaim:   associatedClaim,
+			ClaimNamespace:    claimNamespace,
+			StorageClass:      pv.Spec.StorageClassName,
+			VolumeMode:        volumeMode,
+			Driver:            driver,
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return pvInfos, nil
 }
 
-func fetchPersistentVolumeClaims(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.PersistentVolumeClaimInfo, error) {
-	pvcs, err := clientset.CoreV1().PersistentVolumeClaims("").List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func fetchPersistentVolumeClaims(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.PersistentVolumeClaimInfo, error) {
 	var pvcInfos []k8sdata.PersistentVolumeClaimInfo
-	for _, pvc := range pvcs.Items {
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		pvc := obj.(*corev1.PersistentVolumeClaim)
 		storageClassName := ""
 		if pvc.Spec.StorageClassName != nil {
 			storageClassName = *pvc.Spec.StorageClassName
 		}
+		var accessModes []string
+		for _, mode := range pvc.Spec.AccessModes {
+			accessModes = append(accessModes, string(mode))
+		}
 		pvcInfos = append(pvcInfos, k8sdata.PersistentVolumeClaimInfo{
-			Name:         pvc.Name,
-			Namespace:    pvc.Namespace,
-			Status:       string(pvc.Status.Phase),
-			Volume:       pvc.Spec.VolumeName,
-			Capacity:     pvc.Spec.Resources.Requests.Storage().String(),
-			AccessMode:   string(pvc.Spec.AccessModes[0]),
-			StorageClass: storageClassName,
+			Name:              pvc.Name,
+			Namespace:         pvc.Namespace,
+			CreationTimestamp: creationTimestamp(pvc.CreationTimestamp),
+			Status:            string(pvc.Status.Phase),
+			Volume:            pvc.Spec.VolumeName,
+			Capacity:          pvc.Spec.Resources.Requests.Storage().String(),
+			AccessModes:       accessModes,
+			StorageClass:      storageClassName,
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return pvcInfos, nil
 }
 
-func fetchStorageClasses(ctx context.Context, clientset *kubernetes.Clientset) ([]k8sdata.StorageClassInfo, error) {
-	storageClasses, err := clientset.StorageV1().StorageClasses().List(ctx, v1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func fetchStorageClasses(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.StorageClassInfo, error) {
 	var storageClassInfos []k8sdata.StorageClassInfo
-	for _, sc := range storageClasses.Items {
-		allowVolumeExpansion := "false"
-		if sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion {
-			allowVolumeExpansion = "true"
-		}
+	err := opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.StorageV1().StorageClasses().List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		sc := obj.(*storagev1.StorageClass)
+		allowVolumeExpansion := sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
 		storageClassInfos = append(storageClassInfos, k8sdata.StorageClassInfo{
-			Name:            sc.Name,
-			Provisioner:     sc.Provisioner,
-			VolumeExpansion: allowVolumeExpansion,
+			Name:              sc.Name,
+			CreationTimestamp: creationTimestamp(sc.CreationTimestamp),
+			Provisioner:       sc.Provisioner,
+			VolumeExpansion:   allowVolumeExpansion,
+			IsDefault:         sc.Annotations["storageclass.kubernetes.io/is-default-class"] == "true",
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return storageClassInfos, nil
 }
 
-func fetchVolumeSnapshotClasses(ctx context.Context, dynamicClient dynamic.Interface) ([]k8sdata.VolumeSnapshotClassInfo, error) {
+func fetchVolumeSnapshotClasses(ctx context.Context, dynamicClient dynamic.Interface, opts Options) ([]k8sdata.VolumeSnapshotClassInfo, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    "snapshot.storage.k8s.io",
 		Version:  "v1",
 		Resource: "volumesnapshotclasses",
 	}
-	volumeSnapshotClasses, err := dynamicClient.Resource(gvr).List(ctx, v1.ListOptions{})
-	if err != nil {
-		if strings.Contains(err.Error(), "the server could not find the requested resource") {
-			log.Printf("Warning: VolumeSnapshotClasses resource not found in the cluster")
-			return nil, nil
-		}
-		return nil, err
-	}
-
 	var volumeSnapshotClassInfos []k8sdata.VolumeSnapshotClassInfo
-	for _, vsc := range volumeSnapshotClasses.Items {
+	err := opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return dynamicClient.Resource(gvr).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		vsc := obj.(*unstructured.Unstructured)
 		driver, found, err := unstructured.NestedString(vsc.Object, "driver")
 		if err != nil || !found {
-			return nil, fmt.Errorf("failed to get driver for volume snapshot class %s: %v", vsc.GetName(), err)
+			return fmt.Errorf("failed to get driver for volume snapshot class %s: %v", vsc.GetName(), err)
 		}
 		volumeSnapshotClassInfos = append(volumeSnapshotClassInfos, k8sdata.VolumeSnapshotClassInfo{
-			Name:   vsc.GetName(),
-			Driver: driver,
+			Name:              vsc.GetName(),
+			CreationTimestamp: creationTimestamp(vsc.GetCreationTimestamp()),
+			Driver:            driver,
+			IsDefault:         vsc.GetAnnotations()["snapshot.storage.kubernetes.io/is-default-class"] == "true",
 		})
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "the server could not find the requested resource") {
+			log.Printf("Warning: VolumeSnapshotClasses resource not found in the cluster")
+			return nil, nil
+		}
+		return nil, err
 	}
 
 	return volumeSnapshotClassInfos, nil
 }
 
-func fetchVolumeSnapshots(ctx context.Context, dynamicClient dynamic.Interface) ([]k8sdata.VolumeSnapshotInfo, error) {
+func fetchVolumeSnapshots(ctx context.Context, dynamicClient dynamic.Interface, opts Options) ([]k8sdata.VolumeSnapshotInfo, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    "snapshot.storage.k8s.io",
 		Version:  "v1",
 		Resource: "volumesnapshots",
 	}
-	volumeSnapshots, err := dynamicClient.Resource(gvr).List(ctx, v1.ListOptions{})
-	if err != nil {
-		if strings.Contains(err.Error(), "the server could not find the requested resource") {
-			log.Printf("Warning: VolumeSnapshots resource not found in the cluster")
-			return []k8sdata.VolumeSnapshotInfo{}, nil
-		}
-		return nil, err
-	}
-
 	var volumeSnapshotInfos []k8sdata.VolumeSnapshotInfo
-	for _, vs := range volumeSnapshots.Items {
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		vs := obj.(*unstructured.Unstructured)
 		volumeSnapshot := k8sdata.VolumeSnapshotInfo{
-			Name:      vs.GetName(),
-			Namespace: vs.GetNamespace(),
+			Name:              vs.GetName(),
+			Namespace:         vs.GetNamespace(),
+			CreationTimestamp: creationTimestamp(vs.GetCreationTimestamp()),
 		}
 
 		if volumeName, found, err := unstructured.NestedString(vs.Object, "spec", "source", "persistentVolumeClaimName"); err == nil && found {
 			volumeSnapshot.Volume = volumeName
 		}
 
-		if creationTimestamp, found, err := unstructured.NestedString(vs.Object, "metadata", "creationTimestamp"); err == nil && found {
-			volumeSnapshot.CreationTimestamp = creationTimestamp
-		}
-
 		if restoreSize, found, err := unstructured.NestedString(vs.Object, "status", "restoreSize"); err == nil && found {
 			volumeSnapshot.RestoreSize = restoreSize
 		}
@@ -405,6 +554,14 @@ func fetchVolumeSnapshots(ctx context.Context, dynamicClient dynamic.Interface)
 		}
 
 		volumeSnapshotInfos = append(volumeSnapshotInfos, volumeSnapshot)
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "the server could not find the requested resource") {
+			log.Printf("Warning: VolumeSnapshots resource not found in the cluster")
+			return []k8sdata.VolumeSnapshotInfo{}, nil
+		}
+		return nil, err
 	}
 
 	return volumeSnapshotInfos, nil
@@ -0,0 +1,236 @@
+package kollect
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Topology labels, with the deprecated beta labels older clusters still use.
+var (
+	regionLabels       = []string{corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaRegion}
+	zoneLabels         = []string{corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone}
+	instanceTypeLabels = []string{corev1.LabelInstanceTypeStable, corev1.LabelInstanceType}
+)
+
+func fetchNodes(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.NodeInfo, error) {
+	var nodeInfos []k8sdata.NodeInfo
+	err := opts.eachItem(ctx, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().Nodes().List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		node := obj.(*corev1.Node)
+		var roles []string
+		for label := range node.Labels {
+			if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok {
+				roles = append(roles, role)
+			}
+		}
+		sort.Strings(roles)
+		nodeInfo := k8sdata.NodeInfo{
+			Name:                    node.Name,
+			CreationTimestamp:       creationTimestamp(node.CreationTimestamp),
+			Roles:                   roles,
+			Version:                 node.Status.NodeInfo.KubeletVersion,
+			OSImage:                 node.Status.NodeInfo.OSImage,
+			KernelVersion:           node.Status.NodeInfo.KernelVersion,
+			ContainerRuntimeVersion: node.Status.NodeInfo.ContainerRuntimeVersion,
+			OperatingSystem:         node.Status.NodeInfo.OperatingSystem,
+			Architecture:            node.Status.NodeInfo.Architecture,
+			InstanceType:            firstLabel(node.Labels, instanceTypeLabels),
+			Region:                  firstLabel(node.Labels, regionLabels),
+			Zone:                    firstLabel(node.Labels, zoneLabels),
+			Capacity:                nodeResources(node.Status.Capacity),
+			Allocatable:             nodeResources(node.Status.Allocatable),
+			MaxPods:                 node.Status.Allocatable.Pods().Value(),
+			Unschedulable:           node.Spec.Unschedulable,
+		}
+		for _, taint := range node.Spec.Taints {
+			nodeInfo.Taints = append(nodeInfo.Taints, k8sdata.NodeTaint{
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: string(taint.Effect),
+			})
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				nodeInfo.Ready = condition.Status == corev1.ConditionTrue
+			}
+			nodeInfo.Conditions = append(nodeInfo.Conditions, k8sdata.NodeCondition{
+				Type:    string(condition.Type),
+				Status:  string(condition.Status),
+				Reason:  condition.Reason,
+				Message: condition.Message,
+			})
+		}
+		nodeInfos = append(nodeInfos, nodeInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodeInfos, nil
+}
+
+// podsPerNode counts the pods occupying a slot on each node from the Pods
+// inventory, which holds every pod when collection is not scoped.
+func podsPerNode(pods []k8sdata.PodsInfo) map[string]int {
+	counts := map[string]int{}
+	for _, pod := range pods {
+		if pod.NodeName == "" || pod.Status == string(corev1.PodSucceeded) || pod.Status == string(corev1.PodFailed) {
+			continue
+		}
+		counts[pod.NodeName]++
+	}
+	return counts
+}
+
+// setNodePodCounts records each node's pod count from counts. A nil counts
+// means the pods could not be counted, so the nodes' counts stay unknown.
+func setNodePodCounts(nodes []k8sdata.NodeInfo, counts map[string]int) {
+	if counts == nil {
+		return
+	}
+	for i := range nodes {
+		count := counts[nodes[i].Name]
+		nodes[i].PodCount = &count
+	}
+}
+
+// countPodsPerNode counts the pods occupying a slot on each node with its
+// own pod list. Unlike the Pods inventory it ignores namespace and label
+// scoping, since every pod counts towards a node's maxPods.
+func countPodsPerNode(ctx context.Context, clientset *kubernetes.Clientset, opts Options) (map[string]int, error) {
+	selector := fields.AndSelectors(
+		fields.OneTermNotEqualSelector("spec.nodeName", ""),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	)
+	counts := map[string]int{}
+	err := opts.eachPage(ctx, v1.ListOptions{FieldSelector: selector.String()}, func(ctx context.Context, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().Pods(v1.NamespaceAll).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		counts[obj.(*corev1.Pod).Spec.NodeName]++
+		return nil
+	})
+	return counts, err
+}
+
+func nodeResources(list corev1.ResourceList) k8sdata.NodeResources {
+	return k8sdata.NodeResources{
+		CPU:              quantityString(list, corev1.ResourceCPU),
+		Memory:           quantityString(list, corev1.ResourceMemory),
+		EphemeralStorage: quantityString(list, corev1.ResourceEphemeralStorage),
+		Pods:             quantityString(list, corev1.ResourcePods),
+	}
+}
+
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	if quantity, ok := list[name]; ok {
+		return quantity.String()
+	}
+	return ""
+}
+
+func firstLabel(labels map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// summarizeResources totals the collected nodes. It returns nil when no
+// nodes were collected, e.g. in storage-only runs.
+func summarizeResources(nodes []k8sdata.NodeInfo) *k8sdata.ResourceSummary {
+	if len(nodes) == 0 {
+		return nil
+	}
+	summary := &k8sdata.ResourceSummary{
+		Nodes:         len(nodes),
+		Architectures: map[string]int{},
+		Zones:         map[string]int{},
+		Pressure:      map[string]int{},
+	}
+	capacity := newResourceTotals()
+	allocatable := newResourceTotals()
+	podCount, podsCounted := 0, true
+	for _, node := range nodes {
+		if node.Ready {
+			summary.ReadyNodes++
+		}
+		if node.PodCount != nil {
+			podCount += *node.PodCount
+		} else {
+			podsCounted = false
+		}
+		summary.MaxPods += node.MaxPods
+		if node.Architecture != "" {
+			summary.Architectures[node.Architecture]++
+		}
+		if node.Zone != "" {
+			summary.Zones[node.Zone]++
+		}
+		for _, condition := range node.Conditions {
+			if strings.HasSuffix(condition.Type, "Pressure") && condition.Status == string(corev1.ConditionTrue) {
+				summary.Pressure[condition.Type]++
+			}
+		}
+		capacity.add(node.Capacity)
+		allocatable.add(node.Allocatable)
+	}
+	if podsCounted {
+		summary.PodCount = &podCount
+	}
+	summary.Capacity = capacity.resources()
+	summary.Allocatable = allocatable.resources()
+	return summary
+}
+
+type resourceTotals struct {
+	cpu, memory, ephemeralStorage, pods resource.Quantity
+}
+
+func newResourceTotals() *resourceTotals {
+	return &resourceTotals{
+		cpu:              *resource.NewQuantity(0, resource.DecimalSI),
+		memory:           *resource.NewQuantity(0, resource.BinarySI),
+		ephemeralStorage: *resource.NewQuantity(0, resource.BinarySI),
+		pods:             *resource.NewQuantity(0, resource.DecimalSI),
+	}
+}
+
+// add sums r into the totals, skipping values that do not parse.
+func (t *resourceTotals) add(r k8sdata.NodeResources) {
+	for _, v := range []struct {
+		total *resource.Quantity
+		value string
+	}{
+		{&t.cpu, r.CPU},
+		{&t.memory, r.Memory},
+		{&t.ephemeralStorage, r.EphemeralStorage},
+		{&t.pods, r.Pods},
+	} {
+		if quantity, err := resource.ParseQuantity(v.value); err == nil {
+			v.total.Add(quantity)
+		}
+	}
+}
+
+func (t *resourceTotals) resources() k8sdata.NodeResources {
+	return k8sdata.NodeResources{
+		CPU:              t.cpu.String(),
+		Memory:           t.memory.String(),
+		EphemeralStorage: t.ephemeralStorage.String(),
+		Pods:             t.pods.String(),
+	}
+}
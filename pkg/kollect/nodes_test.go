@@ -0,0 +1,68 @@
+package kollect
+
+import (
+	"reflect"
+	"testing"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+)
+
+func TestPodsPerNode(t *testing.T) {
+	pods := []k8sdata.PodsInfo{
+		{Name: "a", NodeName: "node-1", Status: "Running"},
+		{Name: "b", NodeName: "node-1", Status: "Pending"},
+		{Name: "c", NodeName: "node-2", Status: "Running"},
+		{Name: "done", NodeName: "node-2", Status: "Succeeded"},
+		{Name: "crashed", NodeName: "node-2", Status: "Failed"},
+		{Name: "unscheduled", Status: "Pending"},
+	}
+	want := map[string]int{"node-1": 2, "node-2": 1}
+	if got := podsPerNode(pods); !reflect.DeepEqual(got, want) {
+		t.Errorf("podsPerNode = %v, want %v", got, want)
+	}
+}
+
+func TestOptionsScoped(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want bool
+	}{
+		{Options{}, false},
+		{Options{Namespaces: []string{"default"}}, true},
+		{Options{ExcludeNamespaces: []string{"kube-system"}}, true},
+		{Options{LabelSelector: "app=web"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.scoped(); got != tt.want {
+			t.Errorf("%+v scoped = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestNodePodCountsUnknown(t *testing.T) {
+	nodes := []k8sdata.NodeInfo{{Name: "node-1"}, {Name: "node-2"}}
+	setNodePodCounts(nodes, nil)
+	for _, node := range nodes {
+		if node.PodCount != nil {
+			t.Errorf("%s pod count = %d, want unknown", node.Name, *node.PodCount)
+		}
+	}
+	if summary := summarizeResources(nodes); summary.PodCount != nil {
+		t.Errorf("summary pod count = %d, want unknown", *summary.PodCount)
+	}
+
+	setNodePodCounts(nodes, map[string]int{"node-1": 3})
+	for i, want := range []int{3, 0} {
+		if got := nodes[i].PodCount; got == nil || *got != want {
+			t.Errorf("%s pod count = %v, want %d", nodes[i].Name, got, want)
+		}
+	}
+	if summary := summarizeResources(nodes); summary.PodCount == nil || *summary.PodCount != 3 {
+		t.Errorf("summary pod count = %v, want 3", summary.PodCount)
+	}
+
+	nodes = append(nodes, k8sdata.NodeInfo{Name: "node-3"})
+	if summary := summarizeResources(nodes); summary.PodCount != nil {
+		t.Errorf("summary pod count with an uncounted node = %d, want unknown", *summary.PodCount)
+	}
+}
@@ -0,0 +1,132 @@
+package kollect
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/pager"
+)
+
+const (
+	DefaultPageSize = 500
+	DefaultTimeout  = 60 * time.Second
+	DefaultWorkers  = 4
+
+	DefaultSnapshotMaxAge = 24 * time.Hour
+)
+
+// Options tunes how resources are listed from the API server.
+type Options struct {
+	// PageSize is the Limit sent with each List call. Zero disables
+	// chunking and lists everything in one response.
+	PageSize int64
+	// Timeout bounds each individual List call. Zero means no timeout.
+	Timeout time.Duration
+	// Workers is the number of resource kinds fetched concurrently.
+	Workers int
+	// Namespaces restricts namespaced resources to these namespaces. Empty
+	// means all namespaces.
+	Namespaces []string
+	// ExcludeNamespaces are skipped even when listing all namespaces.
+	ExcludeNamespaces []string
+	// LabelSelector is applied to every namespaced List call.
+	LabelSelector string
+	// SnapshotMaxAge is how old the newest ready VolumeSnapshot of a claim
+	// may be before the readiness report flags it.
+	SnapshotMaxAge time.Duration
+	// CRDGroups is the allow-list of API groups whose custom resources are
+	// collected.
+	CRDGroups []string
+}
+
+// DefaultOptions returns the options used when none are configured.
+func DefaultOptions() Options {
+	return Options{
+		PageSize: DefaultPageSize,
+		Timeout:  DefaultTimeout,
+		Workers:  DefaultWorkers,
+
+		SnapshotMaxAge: DefaultSnapshotMaxAge,
+		CRDGroups:      DefaultCRDGroups,
+	}
+}
+
+// scoped reports whether namespaced resources are restricted to some
+// namespaces or labels, so that their lists do not hold every object.
+func (o Options) scoped() bool {
+	return len(o.Namespaces) > 0 || len(o.ExcludeNamespaces) > 0 || o.LabelSelector != ""
+}
+
+type listFunc func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error)
+
+type namespacedListFunc func(ctx context.Context, namespace string, opts v1.ListOptions) (runtime.Object, error)
+
+// eachItem pages through a cluster-scoped list and calls fn for every item.
+// Items are pointers into the page, so fn must copy what it keeps.
+func (o Options) eachItem(ctx context.Context, list listFunc, fn func(obj runtime.Object) error) error {
+	return o.eachPage(ctx, v1.ListOptions{}, list, fn)
+}
+
+// eachNamespacedItem is eachItem for namespaced resources. The namespace
+// and label scoping is applied server side: one list per selected
+// namespace, or a single all-namespaces list with a field selector
+// excluding the skipped ones.
+func (o Options) eachNamespacedItem(ctx context.Context, list namespacedListFunc, fn func(obj runtime.Object) error) error {
+	base := v1.ListOptions{LabelSelector: o.LabelSelector}
+	namespaces := o.scopedNamespaces()
+	if namespaces == nil {
+		var excluded []fields.Selector
+		for _, ns := range o.ExcludeNamespaces {
+			excluded = append(excluded, fields.OneTermNotEqualSelector("metadata.namespace", ns))
+		}
+		if len(excluded) > 0 {
+			base.FieldSelector = fields.AndSelectors(excluded...).String()
+		}
+		namespaces = []string{v1.NamespaceAll}
+	}
+	for _, ns := range namespaces {
+		err := o.eachPage(ctx, base, func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+			return list(ctx, ns, opts)
+		}, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scopedNamespaces returns the selected namespaces minus the excluded ones,
+// or nil when no namespaces were selected.
+func (o Options) scopedNamespaces() []string {
+	if len(o.Namespaces) == 0 {
+		return nil
+	}
+	excluded := make(map[string]bool, len(o.ExcludeNamespaces))
+	for _, ns := range o.ExcludeNamespaces {
+		excluded[ns] = true
+	}
+	namespaces := []string{}
+	for _, ns := range o.Namespaces {
+		if !excluded[ns] {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// eachPage lists using Limit/Continue chunking on top of options.
+func (o Options) eachPage(ctx context.Context, options v1.ListOptions, list listFunc, fn func(obj runtime.Object) error) error {
+	p := pager.New(func(ctx context.Context, opts v1.ListOptions) (runtime.Object, error) {
+		if o.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+			defer cancel()
+		}
+		return list(ctx, opts)
+	})
+	p.PageSize = o.PageSize
+	return p.EachListItem(ctx, options, fn)
+}
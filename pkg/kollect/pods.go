@@ -0,0 +1,135 @@
+package kollect
+
+import (
+	"context"
+	"strings"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+func fetchPods(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.PodsInfo, error) {
+	var podInfos []k8sdata.PodsInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().Pods(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		pod := obj.(*corev1.Pod)
+		podInfo := k8sdata.PodsInfo{
+			Name:              pod.Name,
+			Namespace:         pod.Namespace,
+			CreationTimestamp: creationTimestamp(pod.CreationTimestamp),
+			Status:            string(pod.Status.Phase),
+			NodeName:          pod.Spec.NodeName,
+			QOSClass:          string(pod.Status.QOSClass),
+		}
+		if owner := v1.GetControllerOf(pod); owner != nil {
+			podInfo.OwnerKind = owner.Kind
+			podInfo.OwnerName = owner.Name
+		}
+		podInfo.Containers = append(podInfo.Containers, containerInfos(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true)...)
+		podInfo.Containers = append(podInfo.Containers, containerInfos(pod.Spec.Containers, pod.Status.ContainerStatuses, false)...)
+		for _, volume := range pod.Spec.Volumes {
+			podInfo.Volumes = append(podInfo.Volumes, podVolume(volume))
+			if volume.PersistentVolumeClaim != nil {
+				podInfo.PersistentVolumeClaims = append(podInfo.PersistentVolumeClaims, volume.PersistentVolumeClaim.ClaimName)
+			}
+		}
+		podInfos = append(podInfos, podInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return podInfos, nil
+}
+
+// containerInfos merges container specs with their statuses, which the
+// kubelet reports by container name.
+func containerInfos(containers []corev1.Container, statuses []corev1.ContainerStatus, init bool) []k8sdata.ContainerInfo {
+	byName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	var infos []k8sdata.ContainerInfo
+	for _, container := range containers {
+		info := k8sdata.ContainerInfo{
+			Name:     container.Name,
+			Init:     init,
+			Image:    container.Image,
+			Requests: containerResources(container.Resources.Requests),
+			Limits:   containerResources(container.Resources.Limits),
+		}
+		if status, ok := byName[container.Name]; ok {
+			info.Ready = status.Ready
+			info.RestartCount = status.RestartCount
+			info.State = containerState(status.State)
+			if _, digest, ok := strings.Cut(status.ImageID, "@"); ok {
+				info.ImageDigest = digest
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				info.LastTerminationReason = terminated.Reason
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func containerResources(list corev1.ResourceList) k8sdata.ContainerResources {
+	return k8sdata.ContainerResources{
+		CPU:              quantityString(list, corev1.ResourceCPU),
+		Memory:           quantityString(list, corev1.ResourceMemory),
+		EphemeralStorage: quantityString(list, corev1.ResourceEphemeralStorage),
+	}
+}
+
+// containerState reports Running, Terminated or the reason a container is
+// waiting, e.g. CrashLoopBackOff.
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Terminated != nil:
+		return "Terminated"
+	case state.Waiting != nil && state.Waiting.Reason != "":
+		return state.Waiting.Reason
+	case state.Waiting != nil:
+		return "Waiting"
+	}
+	return ""
+}
+
+func podVolume(volume corev1.Volume) k8sdata.PodVolume {
+	podVolume := k8sdata.PodVolume{Name: volume.Name, Type: k8sdata.VolumeOther}
+	switch {
+	case volume.PersistentVolumeClaim != nil:
+		podVolume.Type = k8sdata.VolumePersistentVolumeClaim
+		podVolume.Source = volume.PersistentVolumeClaim.ClaimName
+	case volume.HostPath != nil:
+		podVolume.Type = k8sdata.VolumeHostPath
+		podVolume.Source = volume.HostPath.Path
+	case volume.EmptyDir != nil:
+		podVolume.Type = k8sdata.VolumeEmptyDir
+		podVolume.Source = string(volume.EmptyDir.Medium)
+	case volume.ConfigMap != nil:
+		podVolume.Type = k8sdata.VolumeConfigMap
+		podVolume.Source = volume.ConfigMap.Name
+	case volume.Secret != nil:
+		podVolume.Type = k8sdata.VolumeSecret
+		podVolume.Source = volume.Secret.SecretName
+	case volume.Projected != nil:
+		podVolume.Type = k8sdata.VolumeProjected
+	case volume.DownwardAPI != nil:
+		podVolume.Type = k8sdata.VolumeDownwardAPI
+	case volume.CSI != nil:
+		podVolume.Type = k8sdata.VolumeCSI
+		podVolume.Source = volume.CSI.Driver
+	case volume.Ephemeral != nil:
+		podVolume.Type = k8sdata.VolumeEphemeral
+	}
+	return podVolume
+}
@@ -0,0 +1,253 @@
+package kollect
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+)
+
+// Points deducted from the readiness score for each class-level warning,
+// on top of the per-claim score.
+const classWarningPenalty = 5
+
+// assessReadiness analyses the collected storage objects for problems that
+// would stop CSI snapshot based backups from working.
+//
+// Every PersistentVolumeClaim is worth 100 points: 50 if its driver can be
+// snapshotted through a VolumeSnapshotClass and 50 if it has a ready
+// snapshot newer than maxAge. The score is the share of those points the
+// claims earn (100 when there are none), minus a small penalty for each
+// StorageClass or VolumeSnapshotClass warning.
+//
+// If a storage object could not be listed, for example because kollect may
+// not read it, the checks that depend on it are skipped rather than failed,
+// and their points are left out of the score. When the claims themselves,
+// or every check on them, could not be made, the score is left unset.
+func assessReadiness(data *k8sdata.K8sData, now time.Time, maxAge time.Duration) *k8sdata.ReadinessReport {
+	report := &k8sdata.ReadinessReport{SnapshotMaxAge: maxAge.String()}
+	add := func(check, severity string, object k8sdata.ObjectRef, format string, args ...interface{}) {
+		report.Findings = append(report.Findings, k8sdata.ReadinessFinding{
+			Check:    check,
+			Severity: severity,
+			Object:   object,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	failed := map[string]string{}
+	for _, e := range data.Errors {
+		failed[e.Resource] = e.Reason
+	}
+	claimsKnown, volumesKnown, storageClassesKnown := true, true, true
+	classesKnown, snapshotsKnown := true, true
+	if reason, ok := failed["PersistentVolumeClaims"]; ok {
+		claimsKnown = false
+		add("ClaimsUnknown", k8sdata.SeverityWarning, k8sdata.ObjectRef{Kind: "PersistentVolumeClaim"}, "PersistentVolumeClaims could not be listed, claims not assessed: %s", reason)
+	}
+	if reason, ok := failed["PersistentVolumes"]; ok {
+		volumesKnown = false
+		add("VolumesUnknown", k8sdata.SeverityInfo, k8sdata.ObjectRef{Kind: "PersistentVolume"}, "PersistentVolumes could not be listed, drivers taken from storage classes: %s", reason)
+	}
+	if reason, ok := failed["StorageClasses"]; ok {
+		storageClassesKnown = false
+		add("StorageClassesUnknown", k8sdata.SeverityInfo, k8sdata.ObjectRef{Kind: "StorageClass"}, "StorageClasses could not be listed, storage class checks skipped: %s", reason)
+	}
+	if reason, ok := failed["VolumeSnapshotClasses"]; ok {
+		classesKnown = false
+		add("SnapshotClassesUnknown", k8sdata.SeverityInfo, k8sdata.ObjectRef{Kind: "VolumeSnapshotClass"}, "VolumeSnapshotClasses could not be listed, snapshot class checks skipped: %s", reason)
+	}
+	if reason, ok := failed["VolumeSnapshots"]; ok {
+		snapshotsKnown = false
+		add("SnapshotsUnknown", k8sdata.SeverityInfo, k8sdata.ObjectRef{Kind: "VolumeSnapshot"}, "VolumeSnapshots could not be listed, snapshot age checks skipped: %s", reason)
+	}
+
+	provisioners := make(map[string]string, len(data.StorageClasses))
+	for _, sc := range data.StorageClasses {
+		provisioners[sc.Name] = sc.Provisioner
+	}
+	volumeDrivers := make(map[string]string, len(data.PersistentVolumes))
+	for _, pv := range data.PersistentVolumes {
+		volumeDrivers[pv.Name] = pv.Driver
+	}
+	snapshotClasses := map[string][]string{}
+	defaultSnapshotClasses := map[string][]string{}
+	for _, vsc := range data.VolumeSnapshotClasses {
+		snapshotClasses[vsc.Driver] = append(snapshotClasses[vsc.Driver], vsc.Name)
+		if vsc.IsDefault {
+			defaultSnapshotClasses[vsc.Driver] = append(defaultSnapshotClasses[vsc.Driver], vsc.Name)
+		}
+	}
+	latestSnapshots := map[string]time.Time{}
+	for _, vs := range data.VolumeSnapshots {
+		if !vs.Status || vs.Volume == "" {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, vs.CreationTimestamp)
+		if err != nil {
+			continue
+		}
+		key := vs.Namespace + "/" + vs.Volume
+		if created.After(latestSnapshots[key]) {
+			latestSnapshots[key] = created
+		}
+	}
+
+	classWarnings := 0
+	var defaults []string
+	for _, sc := range data.StorageClasses {
+		ref := k8sdata.ObjectRef{Kind: "StorageClass", Name: sc.Name}
+		if sc.IsDefault {
+			defaults = append(defaults, sc.Name)
+		}
+		if isInTree(sc.Provisioner) {
+			add("InTreeProvisioner", k8sdata.SeverityWarning, ref, "provisioner %s is in-tree and cannot be snapshotted; migrate to a CSI driver", sc.Provisioner)
+			classWarnings++
+		}
+		if !sc.VolumeExpansion {
+			add("NoVolumeExpansion", k8sdata.SeverityWarning, ref, "volume expansion is not allowed, restored volumes cannot be grown")
+			classWarnings++
+		}
+	}
+	switch {
+	case len(defaults) > 1:
+		sort.Strings(defaults)
+		add("MultipleDefaultStorageClasses", k8sdata.SeverityWarning, k8sdata.ObjectRef{Kind: "StorageClass"}, "%d StorageClasses are marked default: %s", len(defaults), strings.Join(defaults, ", "))
+		classWarnings++
+	case len(defaults) == 0 && len(data.StorageClasses) > 0:
+		add("NoDefaultStorageClass", k8sdata.SeverityInfo, k8sdata.ObjectRef{Kind: "StorageClass"}, "no StorageClass is marked default")
+	}
+	for driver, names := range defaultSnapshotClasses {
+		if len(names) > 1 {
+			sort.Strings(names)
+			add("MultipleDefaultSnapshotClasses", k8sdata.SeverityWarning, k8sdata.ObjectRef{Kind: "CSIDriver", Name: driver}, "%d VolumeSnapshotClasses are marked default for this driver: %s", len(names), strings.Join(names, ", "))
+			classWarnings++
+		}
+	}
+
+	claims := data.PersistentVolumeClaims
+	if !claimsKnown {
+		// A partial list would score only the claims that were read.
+		claims = nil
+	}
+	total, possible := 0, 0
+	for _, pvc := range claims {
+		ref := k8sdata.ObjectRef{Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name}
+		report.ClaimsAssessed++
+		points, claimPossible := 0, 0
+
+		driver := volumeDrivers[pvc.Volume]
+		if driver == "" {
+			driver = provisioners[pvc.StorageClass]
+		}
+		switch {
+		case driver == "" && (!volumesKnown || !storageClassesKnown):
+			// The objects naming the driver could not be listed.
+		case driver == "":
+			add("UnknownDriver", k8sdata.SeverityWarning, ref, "cannot determine the provisioner for storage class %q", pvc.StorageClass)
+			claimPossible += 50
+		case isInTree(driver):
+			add("InTreeProvisioner", k8sdata.SeverityCritical, ref, "volume is provisioned by in-tree %s and cannot be snapshotted", driver)
+			claimPossible += 50
+		case !classesKnown:
+		case len(snapshotClasses[driver]) == 0:
+			add("NoSnapshotClass", k8sdata.SeverityCritical, ref, "no VolumeSnapshotClass exists for driver %s", driver)
+			claimPossible += 50
+		default:
+			points += 50
+			claimPossible += 50
+		}
+
+		if snapshotsKnown {
+			claimPossible += 50
+			latest, ok := latestSnapshots[pvc.Namespace+"/"+pvc.Name]
+			switch {
+			case !ok:
+				add("NoSnapshot", k8sdata.SeverityWarning, ref, "claim has no ready VolumeSnapshot")
+			case now.Sub(latest) > maxAge:
+				add("StaleSnapshot", k8sdata.SeverityWarning, ref, "newest ready VolumeSnapshot is from %s, older than %s", latest.Format(time.RFC3339), maxAge)
+			default:
+				points += 50
+			}
+		}
+
+		// A claim is only ready when every check could be made.
+		if points == 100 {
+			report.ClaimsReady++
+		}
+		total += points
+		possible += claimPossible
+	}
+
+	switch {
+	case !claimsKnown:
+	case report.ClaimsAssessed > 0 && possible == 0:
+		add("ScoreUnknown", k8sdata.SeverityWarning, k8sdata.ObjectRef{Kind: "PersistentVolumeClaim"}, "none of the checks on the %d claims could be made", report.ClaimsAssessed)
+	default:
+		score := 100
+		if possible > 0 {
+			score = total * 100 / possible
+		}
+		score -= classWarnings * classWarningPenalty
+		if score < 0 {
+			score = 0
+		}
+		report.Score = &score
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) < severityRank(b.Severity)
+		}
+		return refLess(a.Object, b.Object)
+	})
+	return report
+}
+
+// isInTree reports whether a provisioner is one of the legacy in-tree
+// volume plugins, which have no snapshot support.
+func isInTree(provisioner string) bool {
+	return strings.HasPrefix(provisioner, "kubernetes.io/")
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case k8sdata.SeverityCritical:
+		return 0
+	case k8sdata.SeverityWarning:
+		return 1
+	}
+	return 2
+}
+
+// WriteReadinessTable renders a readiness report as an aligned text table.
+func WriteReadinessTable(w io.Writer, report *k8sdata.ReadinessReport) error {
+	if report == nil {
+		_, err := fmt.Fprintln(w, "No readiness report available")
+		return err
+	}
+	score := "unknown"
+	if report.Score != nil {
+		score = fmt.Sprintf("%d/100", *report.Score)
+	}
+	fmt.Fprintf(w, "Backup readiness score: %s (%d of %d claims ready, snapshot max age %s)\n\n",
+		score, report.ClaimsReady, report.ClaimsAssessed, report.SnapshotMaxAge)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tCHECK\tOBJECT\tMESSAGE")
+	for _, f := range report.Findings {
+		object := f.Object.Kind
+		if f.Object.Name != "" {
+			object += "/" + f.Object.Name
+		}
+		if f.Object.Namespace != "" {
+			object = f.Object.Namespace + "/" + object
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Severity, f.Check, object, f.Message)
+	}
+	return tw.Flush()
+}
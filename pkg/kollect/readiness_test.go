@@ -0,0 +1,202 @@
+package kollect
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+)
+
+// readyCluster returns a cluster with one claim on a CSI volume that has a
+// snapshot class and a recent snapshot.
+func readyCluster(now time.Time) *k8sdata.K8sData {
+	return &k8sdata.K8sData{
+		StorageClasses: []k8sdata.StorageClassInfo{
+			{Name: "csi", Provisioner: "ebs.csi.aws.com", VolumeExpansion: true, IsDefault: true},
+		},
+		VolumeSnapshotClasses: []k8sdata.VolumeSnapshotClassInfo{
+			{Name: "snap", Driver: "ebs.csi.aws.com", IsDefault: true},
+		},
+		PersistentVolumes: []k8sdata.PersistentVolumeInfo{
+			{Name: "pv-data", Status: "Bound", AssociatedClaim: "data", ClaimNamespace: "default", StorageClass: "csi", Driver: "ebs.csi.aws.com"},
+		},
+		PersistentVolumeClaims: []k8sdata.PersistentVolumeClaimInfo{
+			{Name: "data", Namespace: "default", Status: "Bound", Volume: "pv-data", StorageClass: "csi"},
+		},
+		VolumeSnapshots: []k8sdata.VolumeSnapshotInfo{
+			{Name: "data-1", Namespace: "default", Volume: "data", Status: true, CreationTimestamp: now.Add(-time.Hour).Format(time.RFC3339)},
+		},
+	}
+}
+
+func failedFetch(resources ...string) []k8sdata.CollectionError {
+	var errs []k8sdata.CollectionError
+	for _, resource := range resources {
+		errs = append(errs, k8sdata.CollectionError{Resource: resource, Reason: "forbidden", Forbidden: true})
+	}
+	return errs
+}
+
+func TestAssessReadiness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	const unknown = -1
+	tests := []struct {
+		name   string
+		modify func(data *k8sdata.K8sData)
+		score  int
+		ready  int
+		checks []string
+	}{
+		{
+			name:  "ready",
+			score: 100,
+			ready: 1,
+		},
+		{
+			name:   "CSI driver without snapshot class",
+			modify: func(data *k8sdata.K8sData) { data.VolumeSnapshotClasses = nil },
+			score:  50,
+			checks: []string{"NoSnapshotClass"},
+		},
+		{
+			name: "in-tree provisioner",
+			modify: func(data *k8sdata.K8sData) {
+				data.StorageClasses[0].Provisioner = "kubernetes.io/aws-ebs"
+				data.PersistentVolumes[0].Driver = ""
+			},
+			score:  45,
+			checks: []string{"InTreeProvisioner", "InTreeProvisioner"},
+		},
+		{
+			name:   "no volume expansion",
+			modify: func(data *k8sdata.K8sData) { data.StorageClasses[0].VolumeExpansion = false },
+			score:  95,
+			ready:  1,
+			checks: []string{"NoVolumeExpansion"},
+		},
+		{
+			name: "multiple default storage classes",
+			modify: func(data *k8sdata.K8sData) {
+				data.StorageClasses = append(data.StorageClasses, k8sdata.StorageClassInfo{Name: "csi-fast", Provisioner: "ebs.csi.aws.com", VolumeExpansion: true, IsDefault: true})
+			},
+			score:  95,
+			ready:  1,
+			checks: []string{"MultipleDefaultStorageClasses"},
+		},
+		{
+			name: "multiple default snapshot classes",
+			modify: func(data *k8sdata.K8sData) {
+				data.VolumeSnapshotClasses = append(data.VolumeSnapshotClasses, k8sdata.VolumeSnapshotClassInfo{Name: "snap-2", Driver: "ebs.csi.aws.com", IsDefault: true})
+			},
+			score:  95,
+			ready:  1,
+			checks: []string{"MultipleDefaultSnapshotClasses"},
+		},
+		{
+			name: "snapshot older than the max age",
+			modify: func(data *k8sdata.K8sData) {
+				data.VolumeSnapshots[0].CreationTimestamp = now.Add(-48 * time.Hour).Format(time.RFC3339)
+			},
+			score:  50,
+			checks: []string{"StaleSnapshot"},
+		},
+		{
+			name:   "no snapshot",
+			modify: func(data *k8sdata.K8sData) { data.VolumeSnapshots[0].Status = false },
+			score:  50,
+			checks: []string{"NoSnapshot"},
+		},
+		{
+			name:   "no claims",
+			modify: func(data *k8sdata.K8sData) { data.PersistentVolumeClaims = nil },
+			score:  100,
+		},
+		{
+			name: "claims could not be listed",
+			modify: func(data *k8sdata.K8sData) {
+				data.PersistentVolumeClaims = nil
+				data.Errors = failedFetch("PersistentVolumeClaims")
+			},
+			score:  unknown,
+			checks: []string{"ClaimsUnknown"},
+		},
+		{
+			name: "volumes and storage classes could not be listed",
+			modify: func(data *k8sdata.K8sData) {
+				data.PersistentVolumes = nil
+				data.StorageClasses = nil
+				data.Errors = failedFetch("PersistentVolumes", "StorageClasses")
+			},
+			score:  100,
+			checks: []string{"StorageClassesUnknown", "VolumesUnknown"},
+		},
+		{
+			name: "volumes could not be listed",
+			modify: func(data *k8sdata.K8sData) {
+				data.PersistentVolumes = nil
+				data.Errors = failedFetch("PersistentVolumes")
+			},
+			score:  100,
+			ready:  1,
+			checks: []string{"VolumesUnknown"},
+		},
+		{
+			name: "snapshot classes could not be listed",
+			modify: func(data *k8sdata.K8sData) {
+				data.VolumeSnapshotClasses = nil
+				data.Errors = failedFetch("VolumeSnapshotClasses")
+			},
+			score:  100,
+			checks: []string{"SnapshotClassesUnknown"},
+		},
+		{
+			name: "snapshots could not be listed",
+			modify: func(data *k8sdata.K8sData) {
+				data.VolumeSnapshots = nil
+				data.Errors = failedFetch("VolumeSnapshots")
+			},
+			score:  100,
+			checks: []string{"SnapshotsUnknown"},
+		},
+		{
+			name: "no check could be made",
+			modify: func(data *k8sdata.K8sData) {
+				data.VolumeSnapshotClasses = nil
+				data.VolumeSnapshots = nil
+				data.Errors = failedFetch("VolumeSnapshotClasses", "VolumeSnapshots")
+			},
+			score:  unknown,
+			checks: []string{"ScoreUnknown", "SnapshotClassesUnknown", "SnapshotsUnknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := readyCluster(now)
+			if tt.modify != nil {
+				tt.modify(data)
+			}
+			report := assessReadiness(data, now, 24*time.Hour)
+
+			score := unknown
+			if report.Score != nil {
+				score = *report.Score
+			}
+			if score != tt.score {
+				t.Errorf("score = %d, want %d", score, tt.score)
+			}
+			if report.ClaimsReady != tt.ready {
+				t.Errorf("claims ready = %d, want %d", report.ClaimsReady, tt.ready)
+			}
+			var checks []string
+			for _, f := range report.Findings {
+				checks = append(checks, f.Check)
+			}
+			sort.Strings(checks)
+			if !reflect.DeepEqual(checks, tt.checks) {
+				t.Errorf("findings = %q, want %q", checks, tt.checks)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package kollect
+
+import (
+	"context"
+	"time"
+
+	k8sdata "github.com/michaelcade/kollect/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+func fetchDaemonSets(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.DaemonSetInfo, error) {
+	var daemonSetInfos []k8sdata.DaemonSetInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.AppsV1().DaemonSets(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		daemonSet := obj.(*appsv1.DaemonSet)
+		var images []string
+		for _, container := range daemonSet.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		daemonSetInfos = append(daemonSetInfos, k8sdata.DaemonSetInfo{
+			Name:                   daemonSet.Name,
+			Namespace:              daemonSet.Namespace,
+			CreationTimestamp:      creationTimestamp(daemonSet.CreationTimestamp),
+			DesiredNumberScheduled: daemonSet.Status.DesiredNumberScheduled,
+			NumberReady:            daemonSet.Status.NumberReady,
+			Images:                 images,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return daemonSetInfos, nil
+}
+
+func fetchReplicaSets(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.ReplicaSetInfo, error) {
+	var replicaSetInfos []k8sdata.ReplicaSetInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.AppsV1().ReplicaSets(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		replicaSet := obj.(*appsv1.ReplicaSet)
+		replicaSetInfo := k8sdata.ReplicaSetInfo{
+			Name:              replicaSet.Name,
+			Namespace:         replicaSet.Namespace,
+			CreationTimestamp: creationTimestamp(replicaSet.CreationTimestamp),
+			ReadyReplicas:     replicaSet.Status.ReadyReplicas,
+		}
+		if replicaSet.Spec.Replicas != nil {
+			replicaSetInfo.Replicas = *replicaSet.Spec.Replicas
+		}
+		if owner := v1.GetControllerOf(replicaSet); owner != nil {
+			replicaSetInfo.OwnerKind = owner.Kind
+			replicaSetInfo.OwnerName = owner.Name
+		}
+		replicaSetInfos = append(replicaSetInfos, replicaSetInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replicaSetInfos, nil
+}
+
+func fetchJobs(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.JobInfo, error) {
+	var jobInfos []k8sdata.JobInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.BatchV1().Jobs(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		job := obj.(*batchv1.Job)
+		jobInfo := k8sdata.JobInfo{
+			Name:              job.Name,
+			Namespace:         job.Namespace,
+			CreationTimestamp: creationTimestamp(job.CreationTimestamp),
+			Succeeded:         job.Status.Succeeded,
+			Failed:            job.Status.Failed,
+			Active:            job.Status.Active,
+		}
+		if job.Spec.Completions != nil {
+			jobInfo.Completions = *job.Spec.Completions
+		}
+		if owner := v1.GetControllerOf(job); owner != nil {
+			jobInfo.OwnerKind = owner.Kind
+			jobInfo.OwnerName = owner.Name
+		}
+		jobInfos = append(jobInfos, jobInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobInfos, nil
+}
+
+func fetchCronJobs(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.CronJobInfo, error) {
+	var cronJobInfos []k8sdata.CronJobInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.BatchV1().CronJobs(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		cronJob := obj.(*batchv1.CronJob)
+		lastScheduleTime := ""
+		if cronJob.Status.LastScheduleTime != nil {
+			lastScheduleTime = cronJob.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+		}
+		cronJobInfos = append(cronJobInfos, k8sdata.CronJobInfo{
+			Name:              cronJob.Name,
+			Namespace:         cronJob.Namespace,
+			CreationTimestamp: creationTimestamp(cronJob.CreationTimestamp),
+			Schedule:          cronJob.Spec.Schedule,
+			Suspend:           cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+			LastScheduleTime:  lastScheduleTime,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cronJobInfos, nil
+}
+
+func fetchIngresses(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.IngressInfo, error) {
+	var ingressInfos []k8sdata.IngressInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.NetworkingV1().Ingresses(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		ingress := obj.(*networkingv1.Ingress)
+		ingressClass := ""
+		if ingress.Spec.IngressClassName != nil {
+			ingressClass = *ingress.Spec.IngressClassName
+		}
+		var hosts []string
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				hosts = append(hosts, rule.Host)
+			}
+		}
+		ingressInfos = append(ingressInfos, k8sdata.IngressInfo{
+			Name:              ingress.Name,
+			Namespace:         ingress.Namespace,
+			CreationTimestamp: creationTimestamp(ingress.CreationTimestamp),
+			IngressClass:      ingressClass,
+			Hosts:             hosts,
+			TLS:               len(ingress.Spec.TLS) > 0,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ingressInfos, nil
+}
+
+func fetchConfigMaps(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.ConfigMapInfo, error) {
+	var configMapInfos []k8sdata.ConfigMapInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().ConfigMaps(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		configMap := obj.(*corev1.ConfigMap)
+		configMapInfos = append(configMapInfos, k8sdata.ConfigMapInfo{
+			Name:              configMap.Name,
+			Namespace:         configMap.Namespace,
+			CreationTimestamp: creationTimestamp(configMap.CreationTimestamp),
+			Keys:              len(configMap.Data) + len(configMap.BinaryData),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return configMapInfos, nil
+}
+
+func fetchSecrets(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.SecretInfo, error) {
+	var secretInfos []k8sdata.SecretInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.CoreV1().Secrets(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		secret := obj.(*corev1.Secret)
+		secretInfos = append(secretInfos, k8sdata.SecretInfo{
+			Name:              secret.Name,
+			Namespace:         secret.Namespace,
+			CreationTimestamp: creationTimestamp(secret.CreationTimestamp),
+			Type:              string(secret.Type),
+			Keys:              len(secret.Data),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secretInfos, nil
+}
+
+func fetchHorizontalPodAutoscalers(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.HorizontalPodAutoscalerInfo, error) {
+	var hpaInfos []k8sdata.HorizontalPodAutoscalerInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		hpa := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+		hpaInfo := k8sdata.HorizontalPodAutoscalerInfo{
+			Name:              hpa.Name,
+			Namespace:         hpa.Namespace,
+			CreationTimestamp: creationTimestamp(hpa.CreationTimestamp),
+			TargetKind:        hpa.Spec.ScaleTargetRef.Kind,
+			TargetName:        hpa.Spec.ScaleTargetRef.Name,
+			MaxReplicas:       hpa.Spec.MaxReplicas,
+			CurrentReplicas:   hpa.Status.CurrentReplicas,
+		}
+		if hpa.Spec.MinReplicas != nil {
+			hpaInfo.MinReplicas = *hpa.Spec.MinReplicas
+		}
+		hpaInfos = append(hpaInfos, hpaInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hpaInfos, nil
+}
+
+func fetchPodDisruptionBudgets(ctx context.Context, clientset *kubernetes.Clientset, opts Options) ([]k8sdata.PodDisruptionBudgetInfo, error) {
+	var pdbInfos []k8sdata.PodDisruptionBudgetInfo
+	err := opts.eachNamespacedItem(ctx, func(ctx context.Context, namespace string, lo v1.ListOptions) (runtime.Object, error) {
+		return clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, lo)
+	}, func(obj runtime.Object) error {
+		pdb := obj.(*policyv1.PodDisruptionBudget)
+		pdbInfo := k8sdata.PodDisruptionBudgetInfo{
+			Name:               pdb.Name,
+			Namespace:          pdb.Namespace,
+			CreationTimestamp:  creationTimestamp(pdb.CreationTimestamp),
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		}
+		if pdb.Spec.MinAvailable != nil {
+			pdbInfo.MinAvailable = pdb.Spec.MinAvailable.String()
+		}
+		if pdb.Spec.MaxUnavailable != nil {
+			pdbInfo.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		}
+		pdbInfos = append(pdbInfos, pdbInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pdbInfos, nil
+}
@@ -0,0 +1,161 @@
+// Package metrics writes inventory counts and collector health in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+	"github.com/michaelcade/kollect/pkg/history"
+)
+
+// ContentType is the media type of the exposition format written by Write.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// resourceLabels are the labels of kollect_resources, in output order.
+var resourceLabels = []string{"platform", "cluster", "account", "type", "namespace", "region", "status"}
+
+// statusFields names the field reported as the status label for each
+// resource type: pod phase, claim status, EC2 state, Veeam job last result.
+var statusFields = map[string]string{
+	"pods":                   "status",
+	"persistentVolumeClaims": "status",
+	"persistentVolumes":      "status",
+	"velero.backups":         "phase",
+	"ec2Instances":           "state",
+	"ebsVolumes":             "state",
+	"ebsSnapshots":           "state",
+	"rdsInstances":           "status",
+	"dynamoDBTables":         "status",
+	"BackupJobs":             "lastResult",
+}
+
+// regionFields are tried in order for the region label; Azure calls it
+// location.
+var regionFields = []string{"region", "location"}
+
+// Counts holds the number of resources per kollect_resources label set. It
+// is computed once per document with Count, so that writing metrics does
+// not walk the document again.
+type Counts map[string]int
+
+// Count counts the resources of doc by their kollect_resources labels.
+func Count(doc collector.Document) (Counts, error) {
+	objects, err := history.Objects(doc)
+	if err != nil {
+		return nil, err
+	}
+	counts := Counts{}
+	for _, object := range objects {
+		values := []string{object.Platform, "", "", object.Type, field(object.Fields, "namespace"), "", ""}
+		if object.ScopeKind == "account" {
+			values[2] = object.Scope
+		} else {
+			values[1] = object.Scope
+		}
+		for _, name := range regionFields {
+			if region := field(object.Fields, name); region != "" {
+				values[5] = region
+				break
+			}
+		}
+		if name, ok := statusFields[object.Type]; ok {
+			values[6] = field(object.Fields, name)
+		}
+		counts[labels(resourceLabels, values)]++
+	}
+	return counts, nil
+}
+
+// Write writes a gauge of the resource counts and the health of every
+// source in status.
+func Write(w io.Writer, counts Counts, status map[string]collector.SourceStatus) error {
+	var b strings.Builder
+	family(&b, "kollect_resources", "gauge", "Number of collected resources.")
+	for _, set := range sortedKeys(counts) {
+		fmt.Fprintf(&b, "kollect_resources%s %d\n", set, counts[set])
+	}
+
+	names := make([]string, 0, len(status))
+	for name := range status {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	source := func(name string) string { return labels([]string{"source"}, []string{name}) }
+
+	family(&b, "kollect_collector_up", "gauge", "Whether the last collection of the source succeeded.")
+	for _, name := range names {
+		up := 0
+		if status[name].Status == collector.StatusOK {
+			up = 1
+		}
+		fmt.Fprintf(&b, "kollect_collector_up%s %d\n", source(name), up)
+	}
+	family(&b, "kollect_collector_duration_seconds", "gauge", "Duration of the last collection of the source.")
+	for _, name := range names {
+		if status[name].LastRun != nil {
+			fmt.Fprintf(&b, "kollect_collector_duration_seconds%s %s\n", source(name), float(status[name].DurationSeconds))
+		}
+	}
+	family(&b, "kollect_collector_last_run_timestamp_seconds", "gauge", "Unix time the last collection of the source started.")
+	for _, name := range names {
+		if t := status[name].LastRun; t != nil {
+			fmt.Fprintf(&b, "kollect_collector_last_run_timestamp_seconds%s %d\n", source(name), t.Unix())
+		}
+	}
+	family(&b, "kollect_collector_last_success_timestamp_seconds", "gauge", "Unix time the last successful collection of the source started.")
+	for _, name := range names {
+		if t := status[name].LastSuccess; t != nil {
+			fmt.Fprintf(&b, "kollect_collector_last_success_timestamp_seconds%s %d\n", source(name), t.Unix())
+		}
+	}
+	family(&b, "kollect_collector_runs_total", "counter", "Collections of the source since kollect started.")
+	for _, name := range names {
+		fmt.Fprintf(&b, "kollect_collector_runs_total%s %d\n", source(name), status[name].Runs)
+	}
+	family(&b, "kollect_collector_errors_total", "counter", "Failed collections of the source since kollect started.")
+	for _, name := range names {
+		fmt.Fprintf(&b, "kollect_collector_errors_total%s %d\n", source(name), status[name].Failures)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func family(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels formats a label set. Empty values are kept, so every series of a
+// metric has the same label names.
+func labels(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func field(fields map[string]interface{}, name string) string {
+	s, _ := fields[name].(string)
+	return s
+}
+
+func float(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
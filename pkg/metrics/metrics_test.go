@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelsKeepsEmptyValues(t *testing.T) {
+	got := labels(resourceLabels, []string{"aws", "", "123", "ec2Instances", "", "eu-west-1", `a"b`})
+	want := `{platform="aws",cluster="",account="123",type="ec2Instances",namespace="",region="eu-west-1",status="a\"b"}`
+	if got != want {
+		t.Errorf("labels = %s, want %s", got, want)
+	}
+}
+
+func TestWriteCounts(t *testing.T) {
+	counts := Counts{labels(resourceLabels, []string{"aws", "", "", "ec2Instances", "", "", ""}): 3}
+	var b strings.Builder
+	if err := Write(&b, counts, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `kollect_resources{platform="aws",cluster="",account="",type="ec2Instances",namespace="",region="",status=""} 3`
+	if !strings.Contains(b.String(), want+"\n") {
+		t.Errorf("output does not contain %s:\n%s", want, b.String())
+	}
+}
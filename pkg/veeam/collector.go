@@ -0,0 +1,59 @@
+package veeam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michaelcade/kollect/pkg/collector"
+)
+
+func init() {
+	collector.Register("veeam", NewCollector)
+}
+
+var collectorSchema = []collector.Field{
+	{Name: "veeam-url", Description: "Veeam server URL", Env: "VEEAM_URL", Required: true},
+	{Name: "veeam-username", Description: "Veeam username", Env: "VEEAM_USERNAME", Required: true},
+	{Name: "veeam-password", Description: "Veeam password", Env: "VEEAM_PASSWORD", Required: true, Secret: true},
+}
+
+// Collector collects Veeam Backup & Replication inventory through the
+// collector registry.
+type Collector struct {
+	cfg collector.Config
+}
+
+// NewCollector returns a Veeam collector configured from cfg.
+func NewCollector(cfg collector.Config) collector.Collector {
+	return &Collector{cfg: cfg.Resolve(collectorSchema)}
+}
+
+func (c *Collector) Name() string {
+	return "veeam"
+}
+
+func (c *Collector) Schema() []collector.Field {
+	return collectorSchema
+}
+
+func (c *Collector) InventoryTypes() []collector.Inventory {
+	return []collector.Inventory{VeeamData{}}
+}
+
+func (c *Collector) Collect(ctx context.Context) (collector.Inventory, error) {
+	if err := c.cfg.Validate(collectorSchema); err != nil {
+		return nil, err
+	}
+	return CollectVeeamData(ctx, c.cfg["veeam-url"], c.cfg["veeam-username"], c.cfg["veeam-password"])
+}
+
+// Check only requests an access token rather than walking every endpoint.
+func (c *Collector) Check(ctx context.Context) error {
+	if err := c.cfg.Validate(collectorSchema); err != nil {
+		return err
+	}
+	if _, err := authenticate(c.cfg["veeam-url"], c.cfg["veeam-username"], c.cfg["veeam-password"]); err != nil {
+		return fmt.Errorf("authentication failed: %v", err)
+	}
+	return nil
+}
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type VeeamData struct {
@@ -76,9 +78,49 @@ func CollectVeeamData(ctx context.Context, baseURL, username, password string) (
 		return data, fmt.Errorf("failed to list backup jobs: %v", err)
 	}
 
+	// Job states only add run status to the jobs, so older servers without
+	// the endpoint still return the rest of the inventory.
+	states, err := getJobStates(baseURL, token)
+	if err != nil {
+		log.Printf("Warning: could not get Veeam job states: %v", err)
+	} else {
+		mergeJobStates(data.BackupJobs, states)
+	}
+
+	for _, list := range [][]interface{}{data.Credentials, data.CloudCredentials, data.KMSServers, data.ManagedServers, data.Repositories, data.ScaleOutRepositories, data.Proxies} {
+		for _, object := range list {
+			if objectMap, ok := object.(map[string]interface{}); ok {
+				stampCreation(objectMap)
+			}
+		}
+	}
+	for _, job := range data.BackupJobs {
+		stampCreation(job)
+	}
+
 	return data, nil
 }
 
+// creationFields are the fields the REST API records creation times in,
+// tried in order.
+var creationFields = []string{"creationTime", "createdAt", "creationDate"}
+
+// stampCreation copies the creation time the API returned for object, if
+// any, into creationTimestamp as RFC3339 in UTC, the form every other
+// inventory uses.
+func stampCreation(object map[string]interface{}) {
+	for _, field := range creationFields {
+		value, ok := object[field].(string)
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			object["creationTimestamp"] = t.UTC().Format(time.RFC3339)
+			return
+		}
+	}
+}
+
 func authenticate(baseURL, username, password string) (string, error) {
 	authURL := fmt.Sprintf("%s/api/oauth2/token", baseURL)
 
@@ -205,6 +247,35 @@ func listBackupJobs(baseURL, token string) ([]map[string]interface{}, error) {
 	return jobList, nil
 }
 
+func getJobStates(baseURL, token string) ([]interface{}, error) {
+	return getAPIList(fmt.Sprintf("%s/api/v1/jobs/states", baseURL), token)
+}
+
+// jobStateFields are copied from a job's state onto the job itself.
+var jobStateFields = []string{"status", "lastRun", "lastResult", "nextRun"}
+
+// mergeJobStates adds the run state of each job, reported separately by
+// /jobs/states, to the job with the same id.
+func mergeJobStates(jobs []map[string]interface{}, states []interface{}) {
+	byID := make(map[interface{}]map[string]interface{}, len(states))
+	for _, state := range states {
+		if stateMap, ok := state.(map[string]interface{}); ok {
+			byID[stateMap["id"]] = stateMap
+		}
+	}
+	for _, job := range jobs {
+		state, ok := byID[job["id"]]
+		if !ok {
+			continue
+		}
+		for _, field := range jobStateFields {
+			if _, set := job[field]; !set && state[field] != nil {
+				job[field] = state[field]
+			}
+		}
+	}
+}
+
 func getAPIData(url, token string) (map[string]interface{}, error) {
 	client := &http.Client{
 		Transport: &http.Transport{
@@ -14,7 +14,7 @@ document.getElementById('aws-button').addEventListener('click', () => {
 document.addEventListener('htmx:afterSwap', (event) => {
     if (event.detail.target.id === 'hidden-content') {
         try {
-            const data = JSON.parse(event.detail.xhr.responseText);
+            const data = JSON.parse(event.detail.xhr.responseText).aws || {};
             console.log("Fetched Data:", data); // Log fetched data
             const content = document.getElementById('content');
             const template = document.getElementById('table-template').content;
@@ -38,21 +38,34 @@ document.addEventListener('htmx:afterSwap', (event) => {
                 });
                 content.appendChild(table);
             }
-            if (data.EC2Instances) {
-                createTable('EC2 Instances', data.EC2Instances, ec2InstanceRowTemplate, ['Name', 'Instance ID', 'Type', 'State', 'Region']);
-            }
-            if (data.S3Buckets) {
-                createTable('S3 Buckets', data.S3Buckets, s3BucketRowTemplate, ['Bucket Name', 'Immutable', 'Region']);
-            }
-            if (data.RDSInstances) {
-                createTable('RDS Instances', data.RDSInstances, rdsInstanceRowTemplate, ['Instance ID', 'Engine', 'Status', 'Region']);
-            }
-            if (data.DynamoDBTables) {
-                createTable('DynamoDB Tables', data.DynamoDBTables, dynamoDBTableRowTemplate, ['Table Name', 'Status', 'Region']);
-            }
-            if (data.VPCs) {
-                createTable('VPCs', data.VPCs, vpcRowTemplate, ['VPC ID', 'State', 'Region']);
+            function renderAccount(data, suffix) {
+                if (data.ec2Instances) {
+                    createTable('EC2 Instances' + suffix, data.ec2Instances, ec2InstanceRowTemplate, ['Name', 'Instance ID', 'Type', 'State', 'Region', 'Age']);
+                }
+                if (data.ebsVolumes) {
+                    createTable('EBS Volumes' + suffix, data.ebsVolumes, ebsVolumeRowTemplate, ['Volume ID', 'Type', 'Size (GiB)', 'IOPS', 'Encrypted', 'State', 'Instances', 'Region', 'Last Snapshot', 'Age']);
+                }
+                if (data.ebsSnapshots) {
+                    createTable('EBS Snapshots' + suffix, data.ebsSnapshots, ebsSnapshotRowTemplate, ['Snapshot ID', 'Volume ID', 'Size (GiB)', 'Encrypted', 'State', 'Region', 'Age']);
+                }
+                if (data.s3Buckets) {
+                    createTable('S3 Buckets' + suffix, data.s3Buckets, s3BucketRowTemplate, ['Bucket Name', 'Immutable', 'Region', 'Age']);
+                }
+                if (data.rdsInstances) {
+                    createTable('RDS Instances' + suffix, data.rdsInstances, rdsInstanceRowTemplate, ['Instance ID', 'Engine', 'Status', 'Region', 'Age']);
+                }
+                if (data.dynamoDBTables) {
+                    createTable('DynamoDB Tables' + suffix, data.dynamoDBTables, dynamoDBTableRowTemplate, ['Table Name', 'Status', 'Region', 'Age']);
+                }
+                if (data.vpcs) {
+                    createTable('VPCs' + suffix, data.vpcs, vpcRowTemplate, ['VPC ID', 'State', 'Region']);
+                }
             }
+            renderAccount(data, '');
+            // Multi-account runs keep each account's inventory under its ID.
+            Object.keys(data.accounts || {}).sort().forEach(account => {
+                renderAccount(data.accounts[account], ` (${account})`);
+            });
         } catch (error) {
             console.error("Error processing data:", error);
         }
@@ -60,21 +73,31 @@ document.addEventListener('htmx:afterSwap', (event) => {
 });
 
 function ec2InstanceRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.InstanceID}</td><td>${item.Type}</td><td>${item.State}</td><td>${item.Region}</td>`;
+    return `<td>${item.name}</td><td>${item.instanceID}</td><td>${item.type}</td><td>${item.state}</td><td>${item.region}</td><td>${formatAge(item.creationTimestamp)}</td>`;
+}
+
+function ebsVolumeRowTemplate(item) {
+    const lastSnapshot = item.latestSnapshot ? formatAge(item.latestSnapshot) + ' ago' : 'never';
+    const snapshotCell = item.snapshotStale ? `<strong>${lastSnapshot} (stale)</strong>` : lastSnapshot;
+    return `<td>${item.volumeID}</td><td>${item.type}</td><td>${item.sizeGiB}</td><td>${item.iops || ''}</td><td>${item.encrypted}</td><td>${item.state}</td><td>${(item.instanceIDs || []).join(', ')}</td><td>${item.region}</td><td>${snapshotCell}</td><td>${formatAge(item.creationTimestamp)}</td>`;
+}
+
+function ebsSnapshotRowTemplate(item) {
+    return `<td>${item.snapshotID}</td><td>${item.volumeID}</td><td>${item.sizeGiB}</td><td>${item.encrypted}</td><td>${item.state}</td><td>${item.region}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function s3BucketRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Immutable}</td><td>${item.Region}</td>`;
+    return `<td>${item.name}</td><td>${item.immutable}</td><td>${item.region}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function rdsInstanceRowTemplate(item) {
-    return `<td>${item.InstanceID}</td><td>${item.Engine}</td><td>${item.Status}</td><td>${item.Region}</td>`;
+    return `<td>${item.instanceID}</td><td>${item.engine}</td><td>${item.status}</td><td>${item.region}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function dynamoDBTableRowTemplate(item) {
-    return `<td>${item.TableName}</td><td>${item.Status}</td><td>${item.Region}</td>`;
+    return `<td>${item.tableName}</td><td>${item.status}</td><td>${item.region}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function vpcRowTemplate(item) {
-    return `<td>${item.VPCID}</td><td>${item.State}</td><td>${item.Region}</td>`;
+    return `<td>${item.vpcID}</td><td>${item.state}</td><td>${item.region}</td>`;
 }
\ No newline at end of file
@@ -14,7 +14,7 @@ document.getElementById('azure-button').addEventListener('click', () => {
 document.addEventListener('htmx:afterSwap', (event) => {
     if (event.detail.target.id === 'hidden-content') {
         try {
-            const data = JSON.parse(event.detail.xhr.responseText);
+            const data = JSON.parse(event.detail.xhr.responseText).azure || {};
             console.log("Fetched Data:", data); // Log fetched data
             const content = document.getElementById('content');
             const template = document.getElementById('table-template').content;
@@ -39,10 +39,10 @@ document.addEventListener('htmx:afterSwap', (event) => {
                 content.appendChild(table);
             }
             if (data.AzureVMs) {
-                createTable('Azure VMs', data.AzureVMs, azureVMRowTemplate, ['Name', 'Location', 'VM Size']);
+                createTable('Azure VMs', data.AzureVMs, azureVMRowTemplate, ['Name', 'Location', 'VM Size', 'Age']);
             }
             if (data.AzureStorageAccounts) {
-                createTable('Azure Storage Accounts', data.AzureStorageAccounts, azureStorageAccountRowTemplate, ['Name', 'Location', 'Kind']);
+                createTable('Azure Storage Accounts', data.AzureStorageAccounts, azureStorageAccountRowTemplate, ['Name', 'Location', 'Kind', 'Age']);
             }
             if (data.AzureBlobContainers) {
                 createTable('Azure Blob Containers', data.AzureBlobContainers, azureBlobContainerRowTemplate, ['Name', 'Immutable', 'ID']);
@@ -51,10 +51,10 @@ document.addEventListener('htmx:afterSwap', (event) => {
                 createTable('Azure Virtual Networks', data.AzureVirtualNetworks, azureVirtualNetworkRowTemplate, ['Name', 'Location']);
             }
             if (data.AzureSQLDatabases) {
-                createTable('Azure SQL Databases', data.AzureSQLDatabases, azureSQLDatabaseRowTemplate, ['Name', 'Location']);
+                createTable('Azure SQL Databases', data.AzureSQLDatabases, azureSQLDatabaseRowTemplate, ['Name', 'Location', 'Age']);
             }
             if (data.AzureCosmosDBs) {
-                createTable('Azure CosmosDB Accounts', data.AzureCosmosDBs, azureCosmosDBRowTemplate, ['Name', 'Location']);
+                createTable('Azure CosmosDB Accounts', data.AzureCosmosDBs, azureCosmosDBRowTemplate, ['Name', 'Location', 'Age']);
             }
         } catch (error) {
             console.error("Error processing data:", error);
@@ -63,11 +63,11 @@ document.addEventListener('htmx:afterSwap', (event) => {
 });
 
 function azureVMRowTemplate(item) {
-    return `<td>${item.name}</td><td>${item.location}</td><td>${item.properties.hardwareProfile.vmSize}</td>`;
+    return `<td>${item.name}</td><td>${item.location}</td><td>${item.properties.hardwareProfile.vmSize}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function azureStorageAccountRowTemplate(item) {
-    return `<td>${item.name}</td><td>${item.location}</td><td>${item.kind}</td>`;
+    return `<td>${item.name}</td><td>${item.location}</td><td>${item.kind}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function azureBlobContainerRowTemplate(item) {
@@ -79,11 +79,11 @@ function azureVirtualNetworkRowTemplate(item) {
 }
 
 function azureSQLDatabaseRowTemplate(item) {
-    return `<td>${item.name}</td><td>${item.location}</td>`;
+    return `<td>${item.name}</td><td>${item.location}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function azureCosmosDBRowTemplate(item) {
-    return `<td>${item.name}</td><td>${item.location}</td>`;
+    return `<td>${item.name}</td><td>${item.location}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function updateAzureStatus(status) {
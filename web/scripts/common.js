@@ -8,6 +8,19 @@ function hideLoadingIndicator() {
     document.getElementById('loading-indicator').style.display = 'none';
 }
 
+// formatAge turns an RFC3339 creation timestamp into an age such as
+// "3d4h5m", measured from now so it stays correct for saved data.
+function formatAge(timestamp) {
+    const created = Date.parse(timestamp);
+    if (!timestamp || isNaN(created)) return '';
+    let minutes = Math.max(0, Math.floor((Date.now() - created) / 60000));
+    const days = Math.floor(minutes / 1440);
+    minutes -= days * 1440;
+    const hours = Math.floor(minutes / 60);
+    minutes -= hours * 60;
+    return `${days}d${hours}h${minutes}m`;
+}
+
 document.getElementById('export-button').addEventListener('click', () => {
     showLoadingIndicator();
     fetch('/api/data')
@@ -26,6 +39,10 @@ document.getElementById('export-button').addEventListener('click', () => {
         .finally(() => hideLoadingIndicator());
 });
 
+document.getElementById('export-xlsx-button').addEventListener('click', () => {
+    window.location.href = '/api/export?format=xlsx';
+});
+
 document.getElementById('import-button').addEventListener('click', () => {
     document.getElementById('import-file').click();
 });
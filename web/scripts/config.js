@@ -50,7 +50,7 @@ async function configureAWS() {
             headers: {
                 'Content-Type': 'application/json'
             },
-            body: JSON.stringify({ accessKey, secretKey })
+            body: JSON.stringify({ accessKeyId: accessKey, secretAccessKey: secretKey })
         });
         
         if (response.ok) {
@@ -113,7 +113,7 @@ async function checkConnectionStatus() {
 
         // Azure check - using AzureVMs and AzureStorageAccounts
         const azureIcon = document.getElementById('azure-button');
-        if (data.AzureVMs?.length > 0 || data.AzureStorageAccounts?.length > 0) {
+        if (data.azure?.AzureVMs?.length > 0 || data.azure?.AzureStorageAccounts?.length > 0) {
             console.log('Azure is connected - found VMs or Storage Accounts');
             azureIcon.classList.remove('disconnected', 'not-configured');
             azureIcon.classList.add('connected');
@@ -125,9 +125,9 @@ async function checkConnectionStatus() {
             azureIcon.onclick = () => showConfigPanel('azure-config');
         }
 
-        // AWS check - using EC2Instances and S3Buckets
+        // AWS check - using ec2Instances and s3Buckets
         const awsIcon = document.getElementById('aws-button');
-        if (data.EC2Instances?.length > 0 || data.S3Buckets?.length > 0) {
+        if (data.aws?.ec2Instances?.length > 0 || data.aws?.s3Buckets?.length > 0) {
             console.log('AWS is connected - found EC2 or S3 data');
             awsIcon.classList.remove('disconnected', 'not-configured');
             awsIcon.classList.add('connected');
@@ -155,7 +155,7 @@ async function checkConnectionStatus() {
 
         // Veeam check - using ServerInfo or BackupJobs
         const veeamIcon = document.getElementById('veeam-button');
-        if (data.ServerInfo || data.BackupJobs?.length > 0) {
+        if (data.veeam?.ServerInfo || data.veeam?.BackupJobs?.length > 0) {
             console.log('Veeam is connected - found server info or backup jobs');
             veeamIcon.classList.remove('disconnected', 'not-configured');
             veeamIcon.classList.add('connected');
@@ -452,7 +452,7 @@ async function checkConnectionStatus() {
 
         // Azure check - using AzureVMs and AzureStorageAccounts
         const azureIcon = document.getElementById('azure-button');
-        if (data.AzureVMs?.length > 0 || data.AzureStorageAccounts?.length > 0) {
+        if (data.azure?.AzureVMs?.length > 0 || data.azure?.AzureStorageAccounts?.length > 0) {
             console.log('Azure is connected - found VMs or Storage Accounts');
             azureIcon.classList.remove('disconnected', 'not-configured');
             azureIcon.classList.add('connected');
@@ -464,9 +464,9 @@ async function checkConnectionStatus() {
             azureIcon.onclick = () => showConfigPanel('azure-config');
         }
 
-        // AWS check - using EC2Instances and S3Buckets
+        // AWS check - using ec2Instances and s3Buckets
         const awsIcon = document.getElementById('aws-button');
-        if (data.EC2Instances?.length > 0 || data.S3Buckets?.length > 0) {
+        if (data.aws?.ec2Instances?.length > 0 || data.aws?.s3Buckets?.length > 0) {
             console.log('AWS is connected - found EC2 or S3 data');
             awsIcon.classList.remove('disconnected', 'not-configured');
             awsIcon.classList.add('connected');
@@ -494,7 +494,7 @@ async function checkConnectionStatus() {
 
         // Veeam check - using ServerInfo or BackupJobs
         const veeamIcon = document.getElementById('veeam-button');
-        if (data.ServerInfo || data.BackupJobs?.length > 0) {
+        if (data.veeam?.ServerInfo || data.veeam?.BackupJobs?.length > 0) {
             console.log('Veeam is connected - found server info or backup jobs');
             veeamIcon.classList.remove('disconnected', 'not-configured');
             veeamIcon.classList.add('connected');
@@ -46,7 +46,7 @@ document.addEventListener('htmx:afterSwap', (event) => {
 });
 
 function nodeRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Roles}</td><td>${item.Age}</td><td>${item.Version}</td><td>${item.OSImage}</td>`;
+    return `<td>${item.name}</td><td>${(item.roles || []).join(', ')}</td><td>${formatAge(item.creationTimestamp)}</td><td>${item.version}</td><td>${item.osImage}</td>`;
 }
 
 function defaultRowTemplate(item) {
@@ -54,37 +54,37 @@ function defaultRowTemplate(item) {
 }
 
 function podRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Namespace}</td><td>${item.Status}</td>`;
+    return `<td>${item.name}</td><td>${item.namespace}</td><td>${item.status}</td>`;
 }
 
 function deploymentRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Namespace}</td><td>${item.Containers.join(', ')}</td><td>${item.Images.join(', ')}</td>`;
+    return `<td>${item.name}</td><td>${item.namespace}</td><td>${(item.containers || []).join(', ')}</td><td>${(item.images || []).join(', ')}</td>`;
 }
 
 function stsRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Namespace}</td><td>${item.ReadyReplicas}</td><td>${item.Image}</td>`;
+    return `<td>${item.name}</td><td>${item.namespace}</td><td>${item.readyReplicas}</td><td>${item.image}</td>`;
 }
 
 function serviceRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Namespace}</td><td>${item.Type}</td><td>${item.ClusterIP}</td><td>${item.Ports}</td>`;
+    return `<td>${item.name}</td><td>${item.namespace}</td><td>${item.type}</td><td>${item.clusterIP}</td><td>${(item.ports || []).map(p => `${p.port}/${p.protocol}`).join(', ')}</td>`;
 }
 
 function perVolRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Capacity}</td><td>${item.AccessModes}</td><td>${item.Status}</td><td>${item.AssociatedClaim}</td><td>${item.StorageClass}</td><td>${item.VolumeMode}</td>`;
+    return `<td>${item.name}</td><td>${item.capacity}</td><td>${(item.accessModes || []).join(', ')}</td><td>${item.status}</td><td>${item.associatedClaim}</td><td>${item.storageClass}</td><td>${item.volumeMode}</td>`;
 }
 
 function perVolClaimRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Namespace}</td><td>${item.Status}</td><td>${item.Volume}</td><td>${item.Capacity}</td><td>${item.AccessMode}</td><td>${item.StorageClass}</td>`;
+    return `<td>${item.name}</td><td>${item.namespace}</td><td>${item.status}</td><td>${item.volume}</td><td>${item.capacity}</td><td>${(item.accessModes || []).join(', ')}</td><td>${item.storageClass}</td>`;
 }
 
 function storageClassRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Provisioner}</td><td>${item.VolumeExpansion}</td>`;
+    return `<td>${item.name}</td><td>${item.provisioner}</td><td>${item.volumeExpansion}</td>`;
 }
 
 function volSnapshotClassRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Driver}</td>`;
+    return `<td>${item.name}</td><td>${item.driver}</td>`;
 }
 
 function volumeSnapshotRowTemplate(item) {
-    return `<td>${item.Name}</td><td>${item.Namespace}</td><td>${item.Volume}</td><td>${item.CreationTimestamp}</td><td>${item.RestoreSize}</td><td>${item.Status}</td>`;
+    return `<td>${item.name}</td><td>${item.namespace}</td><td>${item.volume}</td><td>${formatAge(item.creationTimestamp)}</td><td>${item.restoreSize}</td><td>${item.status}</td>`;
 }
\ No newline at end of file
@@ -12,7 +12,7 @@ document.getElementById('veeam-button').addEventListener('click', () => {
 document.addEventListener('htmx:afterSwap', (event) => {
     if (event.detail.target.id === 'hidden-content') {
         try {
-            const data = JSON.parse(event.detail.xhr.responseText);
+            const data = JSON.parse(event.detail.xhr.responseText).veeam || {};
             console.log("Fetched Data:", data); // Log fetched data
             const content = document.getElementById('content');
             const template = document.getElementById('table-template').content;
@@ -40,10 +40,10 @@ document.addEventListener('htmx:afterSwap', (event) => {
                 createTable('Server Info', [data.ServerInfo], serverInfoRowTemplate, ['Name', 'Build Version', 'Database Vendor', 'SQL Server Version', 'VBR ID']);
             }
             if (data.Credentials) {
-                createTable('Credentials', data.Credentials, credentialsRowTemplate, ['Username', 'Description', 'Type']);
+                createTable('Credentials', data.Credentials, credentialsRowTemplate, ['Username', 'Description', 'Type', 'Age']);
             }
             if (data.CloudCredentials) {
-                createTable('Cloud Credentials', data.CloudCredentials, cloudCredentialsRowTemplate, ['Account', 'Description', 'Type']);
+                createTable('Cloud Credentials', data.CloudCredentials, cloudCredentialsRowTemplate, ['Account', 'Description', 'Type', 'Age']);
             }
             if (data.KMSServers) {
                 createTable('KMS Servers', data.KMSServers, kmsServersRowTemplate, ['ID', 'Name']);
@@ -74,7 +74,7 @@ function serverInfoRowTemplate(item) {
 }
 
 function credentialsRowTemplate(item) {
-    return `<td>${item.username}</td><td>${item.description}</td><td>${item.type}</td>`;
+    return `<td>${item.username}</td><td>${item.description}</td><td>${item.type}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function cloudCredentialsRowTemplate(item) {
@@ -98,7 +98,7 @@ function cloudCredentialsRowTemplate(item) {
         default:
             details = 'N/A';
     }
-    return `<td>${details}</td><td>${item.type}</td><td>${item.description}</td>`;
+    return `<td>${details}</td><td>${item.type}</td><td>${item.description}</td><td>${formatAge(item.creationTimestamp)}</td>`;
 }
 
 function kmsServersRowTemplate(item) {